@@ -0,0 +1,45 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+)
+
+type (
+	// BadRequestHandler converts a request which could not be processed due
+	// to malformed input into a response object. The error describing the
+	// malformed input is supplied to the handler.
+	BadRequestHandler func(context.Context, *http.Request, nacelle.Logger, error) response.Response
+
+	tokenBadRequestHandler string
+)
+
+// TokenBadRequestHandler is the unique token to which the router's bad
+// request handler is written to the request context.
+var TokenBadRequestHandler = tokenBadRequestHandler("chevron.bad_request_handler")
+
+// GetBadRequestHandler retrieves the router's bad request handler from the
+// given context. If no handler is registered with this context, the default
+// handler is returned.
+func GetBadRequestHandler(ctx context.Context) BadRequestHandler {
+	if val, ok := ctx.Value(TokenBadRequestHandler).(BadRequestHandler); ok {
+		return val
+	}
+
+	return defaultBadRequestHandler
+}
+
+func setBadRequestHandler(ctx context.Context, handler BadRequestHandler) context.Context {
+	return context.WithValue(ctx, TokenBadRequestHandler, handler)
+}
+
+func defaultBadRequestHandler(ctx context.Context, r *http.Request, logger nacelle.Logger, err error) response.Response {
+	if options, ok := getProblemOptions(ctx); ok {
+		return options.response(r, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Empty(http.StatusBadRequest)
+}
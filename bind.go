@@ -0,0 +1,387 @@
+package chevron
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// BindError describes every field of a request which could not be bound
+	// to the destination value. Fields maps a field's source and name (e.g.
+	// `query.limit` or `body.name`) to a description of the problem.
+	BindError struct {
+		Fields map[string]string
+	}
+
+	binder struct {
+		ctx    context.Context
+		req    *http.Request
+		fields map[string]string
+		body   map[string]json.RawMessage
+	}
+
+	bindSource struct {
+		name   string
+		values func(b *binder, name string) []string
+	}
+)
+
+var bindSources = []bindSource{
+	{"path", (*binder).pathValues},
+	{"query", (*binder).queryValues},
+	{"header", (*binder).headerValues},
+	{"form", (*binder).formValues},
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind populates the fields of the struct pointed to by dst from the given
+// request. Each field is bound from the source named by its tag:
+//
+//	path:"id"          a path parameter
+//	query:"limit"      a query string parameter
+//	header:"X-Tenant"  a request header
+//	form:"name"        a field of a url-encoded form body
+//	json:"name"        a field of a JSON object body
+//
+// Values are converted to the field's type, which may be a string, bool,
+// numeric, time.Duration, or encoding.TextUnmarshaler type (which includes
+// time.Time), or a pointer to or slice of one of these types. A value for
+// a field absent from the request is taken from the field's `default` tag,
+// if one is supplied. A field tagged with `required:"true"` must be present
+// in the request. The fields of embedded structs are bound recursively.
+//
+// If any field cannot be bound, a *BindError describing every offending
+// field is returned. The router's default error mapper converts this error
+// into a 400-level response.
+func Bind(ctx context.Context, req *http.Request, dst interface{}) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind destination must be a non-nil pointer to a struct")
+	}
+
+	b := &binder{
+		ctx:    ctx,
+		req:    req,
+		fields: map[string]string{},
+	}
+
+	if hasJSONFields(value.Elem().Type()) {
+		b.readBody()
+	}
+
+	b.bindStruct(value.Elem())
+
+	if len(b.fields) > 0 {
+		return &BindError{Fields: b.fields}
+	}
+
+	return nil
+}
+
+// Error describes each field that could not be bound, ordered by key.
+func (e *BindError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	descriptions := make([]string, 0, len(keys))
+	for _, key := range keys {
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", key, e.Fields[key]))
+	}
+
+	return fmt.Sprintf("invalid request (%s)", strings.Join(descriptions, "; "))
+}
+
+// StatusCode returns 400.
+func (e *BindError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// FieldErrors returns the names of the fields that could not be bound mapped
+// to a description of the problem.
+func (e *BindError) FieldErrors() map[string]string {
+	return e.Fields
+}
+
+func (b *binder) readBody() {
+	if b.req.Body == nil || !isJSONContentType(b.req.Header.Get("Content-Type")) {
+		return
+	}
+
+	content, err := ioutil.ReadAll(b.req.Body)
+	if err != nil {
+		b.fields["body"] = fmt.Sprintf("could not read body (%s)", err.Error())
+		return
+	}
+
+	// Allow the body to be read again by the caller
+	b.req.Body = ioutil.NopCloser(bytes.NewReader(content))
+
+	if len(bytes.TrimSpace(content)) == 0 {
+		return
+	}
+
+	if err := json.Unmarshal(content, &b.body); err != nil {
+		b.fields["body"] = "malformed JSON object"
+	}
+}
+
+func (b *binder) bindStruct(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && !hasSourceTag(field) {
+			b.bindStruct(value.Field(i))
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		b.bindField(field, value.Field(i))
+	}
+}
+
+func (b *binder) bindField(field reflect.StructField, value reflect.Value) {
+	for _, source := range bindSources {
+		if name, ok := field.Tag.Lookup(source.name); ok {
+			key := fmt.Sprintf("%s.%s", source.name, name)
+			b.bindValues(field, value, key, source.values(b, name))
+			return
+		}
+	}
+
+	if name, ok := jsonFieldName(field); ok {
+		if _, ok := b.fields["body"]; ok {
+			// Body could not be decoded; do not report each field
+			return
+		}
+
+		key := fmt.Sprintf("body.%s", name)
+
+		raw, ok := b.body[name]
+		if !ok {
+			b.bindValues(field, value, key, nil)
+			return
+		}
+
+		if err := json.Unmarshal(raw, value.Addr().Interface()); err != nil {
+			b.fields[key] = describeJSONError(err)
+		}
+	}
+}
+
+func (b *binder) bindValues(field reflect.StructField, value reflect.Value, key string, values []string) {
+	if len(values) == 0 {
+		if defaultValue, ok := field.Tag.Lookup("default"); ok {
+			values = []string{defaultValue}
+
+			if value.Kind() == reflect.Slice {
+				values = strings.Split(defaultValue, ",")
+			}
+		} else {
+			if field.Tag.Get("required") == "true" {
+				b.fields[key] = "required"
+			}
+
+			return
+		}
+	}
+
+	if err := setValue(value, values); err != nil {
+		b.fields[key] = err.Error()
+	}
+}
+
+func (b *binder) pathValues(name string) []string {
+	if value, ok := PathParams(b.ctx)[name]; ok {
+		return []string{value}
+	}
+
+	return nil
+}
+
+func (b *binder) queryValues(name string) []string {
+	return b.req.URL.Query()[name]
+}
+
+func (b *binder) headerValues(name string) []string {
+	return b.req.Header[http.CanonicalHeaderKey(name)]
+}
+
+func (b *binder) formValues(name string) []string {
+	if b.req.PostForm == nil {
+		if err := b.req.ParseForm(); err != nil {
+			b.fields["form"] = "malformed form body"
+		}
+	}
+
+	return b.req.PostForm[name]
+}
+
+func setValue(value reflect.Value, values []string) error {
+	if value.Kind() == reflect.Slice && !value.Addr().Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(value.Type(), len(values), len(values))
+		for i, v := range values {
+			if err := setScalar(slice.Index(i), v); err != nil {
+				return err
+			}
+		}
+
+		value.Set(slice)
+		return nil
+	}
+
+	return setScalar(value, values[0])
+}
+
+func setScalar(value reflect.Value, raw string) error {
+	if value.Kind() == reflect.Ptr {
+		ptr := reflect.New(value.Type().Elem())
+		if err := setScalar(ptr.Elem(), raw); err != nil {
+			return err
+		}
+
+		value.Set(ptr)
+		return nil
+	}
+
+	if value.Addr().Type().Implements(textUnmarshalerType) {
+		if err := value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw)); err != nil {
+			return invalidValue(raw, value.Type())
+		}
+
+		return nil
+	}
+
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return invalidValue(raw, value.Type())
+		}
+
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+
+	case reflect.Bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return invalidValue(raw, value.Type())
+		}
+
+		value.SetBool(v)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return invalidValue(raw, value.Type())
+		}
+
+		value.SetInt(v)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return invalidValue(raw, value.Type())
+		}
+
+		value.SetUint(v)
+
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return invalidValue(raw, value.Type())
+		}
+
+		value.SetFloat(v)
+
+	default:
+		return fmt.Errorf("unsupported field type %s", value.Type())
+	}
+
+	return nil
+}
+
+func invalidValue(raw string, typ reflect.Type) error {
+	return fmt.Errorf("invalid value `%s` (expected %s)", raw, typ)
+}
+
+func describeJSONError(err error) string {
+	if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+		return fmt.Sprintf("invalid %s value (expected %s)", typeErr.Value, typeErr.Type)
+	}
+
+	return "invalid value"
+}
+
+func hasSourceTag(field reflect.StructField) bool {
+	for _, source := range bindSources {
+		if _, ok := field.Tag.Lookup(source.name); ok {
+			return true
+		}
+	}
+
+	_, ok := jsonFieldName(field)
+	return ok
+}
+
+func hasJSONFields(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && !hasSourceTag(field) {
+			if hasJSONFields(field.Type) {
+				return true
+			}
+
+			continue
+		}
+
+		if _, ok := jsonFieldName(field); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return "", false
+	}
+
+	name := strings.Split(tag, ",")[0]
+	if name == "-" {
+		return "", false
+	}
+
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, true
+}
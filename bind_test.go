@@ -0,0 +1,141 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	"github.com/google/uuid"
+	. "github.com/onsi/gomega"
+)
+
+type BindSuite struct{}
+
+type (
+	bindPagination struct {
+		Limit  int `query:"limit" default:"10"`
+		Offset int `query:"offset"`
+	}
+
+	bindRequest struct {
+		bindPagination
+		ID       uuid.UUID     `path:"id"`
+		Tags     []string      `query:"tag"`
+		Since    *time.Time    `query:"since"`
+		Timeout  time.Duration `query:"timeout" default:"5s"`
+		Tenant   string        `header:"X-Tenant" required:"true"`
+		Name     string        `json:"name" required:"true"`
+		Score    float64       `json:"score"`
+		Verified bool          `json:"verified"`
+		ignored  string
+	}
+
+	bindFormRequest struct {
+		Name  string `form:"name" required:"true"`
+		Count uint8  `form:"count"`
+	}
+)
+
+func (s *BindSuite) TestBind(t sweet.T) {
+	var payload bindRequest
+	id := uuid.New()
+
+	recorder := serveBind(&payload, "/users/"+id.String()+"?tag=a&tag=b&since=2019-06-01T12:00:00Z&offset=20", `{"name": "alice", "score": 0.5}`, map[string]string{
+		"Content-Type": "application/json",
+		"X-Tenant":     "acme",
+	})
+
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(payload.ID).To(Equal(id))
+	Expect(payload.Tags).To(Equal([]string{"a", "b"}))
+	Expect(payload.Since).NotTo(BeNil())
+	Expect(*payload.Since).To(Equal(time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)))
+	Expect(payload.Timeout).To(Equal(5 * time.Second))
+	Expect(payload.Limit).To(Equal(10))
+	Expect(payload.Offset).To(Equal(20))
+	Expect(payload.Tenant).To(Equal("acme"))
+	Expect(payload.Name).To(Equal("alice"))
+	Expect(payload.Score).To(Equal(0.5))
+	Expect(payload.Verified).To(BeFalse())
+}
+
+func (s *BindSuite) TestBindErrors(t sweet.T) {
+	var payload bindRequest
+
+	recorder := serveBind(&payload, "/users/foo?limit=ten&since=yesterday", `{"score": "high"}`, map[string]string{
+		"Content-Type": "application/json",
+	})
+
+	Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	Expect(recorder.Body.String()).To(MatchJSON(`{
+		"status": 400,
+		"message": "invalid request (body.name: required; body.score: invalid string value (expected float64); header.X-Tenant: required; path.id: invalid value ` + "`foo`" + ` (expected uuid.UUID); query.limit: invalid value ` + "`ten`" + ` (expected int); query.since: invalid value ` + "`yesterday`" + ` (expected time.Time))",
+		"fields": {
+			"body.name": "required",
+			"body.score": "invalid string value (expected float64)",
+			"header.X-Tenant": "required",
+			"path.id": "invalid value ` + "`foo`" + ` (expected uuid.UUID)",
+			"query.limit": "invalid value ` + "`ten`" + ` (expected int)",
+			"query.since": "invalid value ` + "`yesterday`" + ` (expected time.Time)"
+		}
+	}`))
+}
+
+func (s *BindSuite) TestBindMalformedBody(t sweet.T) {
+	var payload bindRequest
+
+	recorder := serveBind(&payload, "/users/"+uuid.New().String(), `{"name": `, map[string]string{
+		"Content-Type": "application/json",
+		"X-Tenant":     "acme",
+	})
+
+	Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"status": 400, "message": "invalid request (body: malformed JSON object)", "fields": {"body": "malformed JSON object"}}`))
+}
+
+func (s *BindSuite) TestBindForm(t sweet.T) {
+	var payload bindFormRequest
+
+	recorder := serveBind(&payload, "/users/1", "name=bob&count=3", map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+	})
+
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(payload).To(Equal(bindFormRequest{Name: "bob", Count: 3}))
+
+	recorder = serveBind(&payload, "/users/1", "count=300", map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+	})
+
+	Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	Expect(recorder.Body.String()).To(ContainSubstring(`"form.name":"required"`))
+	Expect(recorder.Body.String()).To(ContainSubstring(`"form.count":"invalid value`))
+}
+
+func (s *BindSuite) TestBindInvalidDestination(t sweet.T) {
+	req, _ := http.NewRequest("GET", "/", nil)
+	Expect(Bind(context.Background(), req, bindRequest{})).To(MatchError("bind destination must be a non-nil pointer to a struct"))
+	Expect(Bind(context.Background(), req, new(int))).To(MatchError("bind destination must be a non-nil pointer to a struct"))
+}
+
+//
+//
+
+func serveBind(dst interface{}, url, body string, headers map[string]string) *httptest.ResponseRecorder {
+	handler := HandlerE(func(ctx context.Context, req *http.Request, logger nacelle.Logger) (response.Response, error) {
+		if err := Bind(ctx, req, dst); err != nil {
+			return nil, err
+		}
+
+		return response.Empty(http.StatusOK), nil
+	})
+
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(router.Register("/users/{id}", &EmptySpec{}, WithMethodHandler("POST", handler.Handle))).To(BeNil())
+
+	return serveRequestBody(router, "POST", url, body, headers)
+}
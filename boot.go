@@ -6,20 +6,72 @@ import (
 )
 
 // BootAndExit creates a nacelle Bootstrapper with the given name and
-// registers an HTTP server with the given route initializer. This method
-// does not return.
-func BootAndExit(name string, initializer RouteInitializer) {
+// registers an HTTP server with the given route initializer. Additional
+// services, initializers, processes, and HTTP servers as well as the
+// configuration of the server and its router can be supplied via boot
+// configs. The built-in middleware enabled by the server config requires a
+// middleware factory, which is supplied by middleware.BootAndExit (or via the
+// boot config WithDefaultServerMiddleware(middleware.FromServerConfig)); the
+// servers fail to initialize if such middleware is enabled without one.
+// This method does not return.
+func BootAndExit(name string, initializer RouteInitializer, configs ...BootConfig) {
+	options := getBootOptions(initializer, configs)
+
 	boostrapper := nacelle.NewBootstrapper(
 		name,
-		setupFactory(initializer),
+		setupFactory(options),
+		options.bootstrapperConfigs...,
 	)
 
 	boostrapper.BootAndExit()
 }
 
-func setupFactory(initializer RouteInitializer) func(nacelle.ProcessContainer, nacelle.ServiceContainer) error {
+func setupFactory(options *bootOptions) func(nacelle.ProcessContainer, nacelle.ServiceContainer) error {
 	return func(processes nacelle.ProcessContainer, services nacelle.ServiceContainer) error {
-		processes.RegisterProcess(httpbase.NewServer(NewInitializer(initializer)))
+		for _, service := range options.services {
+			if err := services.Set(service.name, service.value); err != nil {
+				return err
+			}
+		}
+
+		for _, i := range options.initializers {
+			processes.RegisterInitializer(i.initializer, i.configs...)
+		}
+
+		for _, server := range options.servers {
+			processes.RegisterProcess(server.process(options.middlewareFactory), server.processConfigs...)
+		}
+
+		for _, p := range options.processes {
+			processes.RegisterProcess(p.process, p.configs...)
+		}
+
 		return nil
 	}
 }
+
+// process creates an HTTP server which reads its config with the server's
+// config prefix, if one is set. The given middleware factory, if non-nil, is
+// used unless the server supplies its own.
+func (s *bootServer) process(middlewareFactory ServerMiddlewareFactory) nacelle.Process {
+	var (
+		initializerConfigs = s.initializerConfigs
+		serverConfigs      = s.serverConfigs
+	)
+
+	if middlewareFactory != nil {
+		initializerConfigs = append([]InitializerConfig{WithServerMiddleware(middlewareFactory)}, initializerConfigs...)
+	}
+
+	if s.configPrefix != "" {
+		modifiers := []nacelle.TagModifier{
+			nacelle.NewEnvTagPrefixer(s.configPrefix),
+			nacelle.NewFileTagPrefixer(s.configPrefix),
+		}
+
+		initializerConfigs = append([]InitializerConfig{WithConfigTagModifiers(modifiers...)}, initializerConfigs...)
+		serverConfigs = append([]httpbase.ConfigFunc{httpbase.WithTagModifiers(modifiers...)}, serverConfigs...)
+	}
+
+	return NewServer(NewInitializer(s.initializer, initializerConfigs...), serverConfigs...)
+}
@@ -0,0 +1,160 @@
+package chevron
+
+import (
+	"github.com/go-nacelle/httpbase"
+	"github.com/go-nacelle/nacelle"
+)
+
+type (
+	bootOptions struct {
+		servers             []*bootServer
+		bootstrapperConfigs []nacelle.BootstrapperConfigFunc
+		services            []bootService
+		initializers        []bootInitializer
+		processes           []bootProcess
+		middlewareFactory   ServerMiddlewareFactory
+	}
+
+	bootServer struct {
+		initializer        RouteInitializer
+		configPrefix       string
+		initializerConfigs []InitializerConfig
+		serverConfigs      []httpbase.ConfigFunc
+		processConfigs     []nacelle.ProcessConfigFunc
+	}
+
+	bootService struct {
+		name  string
+		value interface{}
+	}
+
+	bootInitializer struct {
+		initializer nacelle.Initializer
+		configs     []nacelle.InitializerConfigFunc
+	}
+
+	bootProcess struct {
+		process nacelle.Process
+		configs []nacelle.ProcessConfigFunc
+	}
+
+	// BootConfig configures BootAndExit. Both BootConfigFunc and
+	// BootServerConfigFunc values conform to this interface. The latter
+	// apply to the server created with the route initializer supplied to
+	// BootAndExit.
+	BootConfig interface {
+		applyBoot(*bootOptions)
+	}
+
+	// BootConfigFunc is a function used to configure BootAndExit.
+	BootConfigFunc func(*bootOptions)
+
+	// BootServerConfigFunc is a function used to configure an HTTP server
+	// registered by BootAndExit.
+	BootServerConfigFunc func(*bootServer)
+)
+
+// applyBoot calls the wrapped function with the given options.
+func (f BootConfigFunc) applyBoot(o *bootOptions) {
+	f(o)
+}
+
+// applyBoot calls the wrapped function with the server created with the
+// route initializer supplied to BootAndExit.
+func (f BootServerConfigFunc) applyBoot(o *bootOptions) {
+	f(o.servers[0])
+}
+
+// WithAdditionalServer registers an additional HTTP server with the given
+// route initializer. The server shares the service container with the other
+// servers, but reads its config (e.g. HTTP_PORT and CHEVRON_GZIP) prefixed
+// by the given name (e.g. ADMIN_HTTP_PORT and ADMIN_CHEVRON_GZIP) unless a
+// different prefix is supplied via WithConfigPrefix. The server's process is
+// registered with the given name.
+func WithAdditionalServer(name string, initializer RouteInitializer, configs ...BootServerConfigFunc) BootConfigFunc {
+	return func(o *bootOptions) {
+		server := &bootServer{
+			initializer:  initializer,
+			configPrefix: name,
+			processConfigs: []nacelle.ProcessConfigFunc{
+				nacelle.WithProcessName(name),
+			},
+		}
+
+		for _, f := range configs {
+			f(server)
+		}
+
+		o.servers = append(o.servers, server)
+	}
+}
+
+// WithConfigPrefix sets the prefix of the env vars and file keys from which
+// the server reads its config. An empty prefix reads the unprefixed config.
+func WithConfigPrefix(prefix string) BootServerConfigFunc {
+	return func(s *bootServer) { s.configPrefix = prefix }
+}
+
+// WithInitializerConfigs supplies configs to the server initializer. This
+// accepts RouterConfigFunc values as well as InitializerConfigFunc values
+// such as WithServerMiddleware.
+func WithInitializerConfigs(configs ...InitializerConfig) BootServerConfigFunc {
+	return func(s *bootServer) { s.initializerConfigs = append(s.initializerConfigs, configs...) }
+}
+
+// WithServerConfigs supplies configs to the underlying httpbase server,
+// such as config tag modifiers.
+func WithServerConfigs(configs ...httpbase.ConfigFunc) BootServerConfigFunc {
+	return func(s *bootServer) { s.serverConfigs = append(s.serverConfigs, configs...) }
+}
+
+// WithServerProcessConfigs supplies configs used when registering the
+// server to the process container, such as its name or priority.
+func WithServerProcessConfigs(configs ...nacelle.ProcessConfigFunc) BootServerConfigFunc {
+	return func(s *bootServer) { s.processConfigs = append(s.processConfigs, configs...) }
+}
+
+// WithBootstrapperConfigs supplies configs to the nacelle bootstrapper,
+// such as the config sourcer.
+func WithBootstrapperConfigs(configs ...nacelle.BootstrapperConfigFunc) BootConfigFunc {
+	return func(o *bootOptions) { o.bootstrapperConfigs = append(o.bootstrapperConfigs, configs...) }
+}
+
+// WithDefaultServerMiddleware sets the factory that creates the middleware
+// enabled by the server config of every server registered by BootAndExit,
+// including additional servers. A factory supplied to a particular server
+// via WithInitializerConfigs takes precedence.
+func WithDefaultServerMiddleware(factory ServerMiddlewareFactory) BootConfigFunc {
+	return func(o *bootOptions) { o.middlewareFactory = factory }
+}
+
+// WithService registers a value to the service container under the given
+// name before any initializer or process is run.
+func WithService(name string, value interface{}) BootConfigFunc {
+	return func(o *bootOptions) { o.services = append(o.services, bootService{name, value}) }
+}
+
+// WithInitializer registers an initializer to the process container. The
+// initializers are run, in order, before the server is initialized, and can
+// be used to register services which require configuration.
+func WithInitializer(initializer nacelle.Initializer, configs ...nacelle.InitializerConfigFunc) BootConfigFunc {
+	return func(o *bootOptions) { o.initializers = append(o.initializers, bootInitializer{initializer, configs}) }
+}
+
+// WithProcess registers a process to the process container, which runs
+// alongside the servers.
+func WithProcess(process nacelle.Process, configs ...nacelle.ProcessConfigFunc) BootConfigFunc {
+	return func(o *bootOptions) { o.processes = append(o.processes, bootProcess{process, configs}) }
+}
+
+func getBootOptions(initializer RouteInitializer, configs []BootConfig) *bootOptions {
+	options := &bootOptions{
+		servers: []*bootServer{{initializer: initializer}},
+	}
+
+	for _, config := range configs {
+		config.applyBoot(options)
+	}
+
+	return options
+}
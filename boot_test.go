@@ -0,0 +1,123 @@
+package chevron
+
+import (
+	"github.com/aphistic/sweet"
+	"github.com/go-nacelle/httpbase"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type BootSuite struct{}
+
+func (s *BootSuite) TestSetupFactoryDefaults(t sweet.T) {
+	var (
+		processes = nacelle.NewProcessContainer()
+		services  = nacelle.NewServiceContainer()
+		setup     = setupFactory(getBootOptions(RouteInitializerFunc(nil), nil))
+	)
+
+	Expect(setup(processes, services)).To(BeNil())
+	Expect(processes.NumInitializers()).To(Equal(0))
+	Expect(processes.NumProcesses()).To(Equal(1))
+	Expect(processes.GetProcessesAtPriorityIndex(0)[0].Wrapped()).To(BeAssignableToTypeOf(&Server{}))
+}
+
+func (s *BootSuite) TestSetupFactoryOptions(t sweet.T) {
+	var (
+		processes   = nacelle.NewProcessContainer()
+		services    = nacelle.NewServiceContainer()
+		initializer = nacelle.InitializerFunc(func(config nacelle.Config) error { return nil })
+		process     = httpbase.NewServer(nil)
+	)
+
+	setup := setupFactory(getBootOptions(RouteInitializerFunc(nil), []BootConfig{
+		WithService("db", "postgres"),
+		WithInitializer(initializer, nacelle.WithInitializerName("cache")),
+		WithProcess(process, nacelle.WithProcessName("admin")),
+		WithServerProcessConfigs(nacelle.WithProcessName("api")),
+	}))
+
+	Expect(setup(processes, services)).To(BeNil())
+
+	value, err := services.Get("db")
+	Expect(err).To(BeNil())
+	Expect(value).To(Equal("postgres"))
+
+	Expect(processes.NumInitializers()).To(Equal(1))
+	Expect(processes.GetInitializers()[0].Name()).To(Equal("cache"))
+
+	registered := processes.GetProcessesAtPriorityIndex(0)
+	Expect(registered).To(HaveLen(2))
+	Expect(registered[0].Name()).To(Equal("api"))
+	Expect(registered[1].Name()).To(Equal("admin"))
+	Expect(registered[1].Wrapped()).To(BeIdenticalTo(process))
+}
+
+func (s *BootSuite) TestSetupFactoryAdditionalServers(t sweet.T) {
+	processes := nacelle.NewProcessContainer()
+
+	setup := setupFactory(getBootOptions(RouteInitializerFunc(nil), []BootConfig{
+		WithAdditionalServer("admin", RouteInitializerFunc(nil)),
+		WithAdditionalServer("metrics", RouteInitializerFunc(nil), WithServerProcessConfigs(nacelle.WithProcessName("prometheus"))),
+		WithServerProcessConfigs(nacelle.WithProcessName("api")),
+	}))
+
+	Expect(setup(processes, nacelle.NewServiceContainer())).To(BeNil())
+
+	registered := processes.GetProcessesAtPriorityIndex(0)
+	Expect(registered).To(HaveLen(3))
+	Expect(registered[0].Name()).To(Equal("api"))
+	Expect(registered[1].Name()).To(Equal("admin"))
+	Expect(registered[2].Name()).To(Equal("prometheus"))
+
+	for _, meta := range registered {
+		Expect(meta.Wrapped()).To(BeAssignableToTypeOf(&Server{}))
+	}
+}
+
+func (s *BootSuite) TestAdditionalServerConfigPrefix(t sweet.T) {
+	options := getBootOptions(RouteInitializerFunc(nil), []BootConfig{
+		WithAdditionalServer("admin", RouteInitializerFunc(nil)),
+		WithAdditionalServer("internal", RouteInitializerFunc(nil), WithConfigPrefix("private")),
+		WithAdditionalServer("public", RouteInitializerFunc(nil), WithConfigPrefix("")),
+	})
+
+	Expect(options.servers).To(HaveLen(4))
+	Expect(options.servers[0].configPrefix).To(BeEmpty())
+	Expect(options.servers[1].configPrefix).To(Equal("admin"))
+	Expect(options.servers[2].configPrefix).To(Equal("private"))
+	Expect(options.servers[3].configPrefix).To(BeEmpty())
+}
+
+func (s *BootSuite) TestDefaultServerMiddleware(t sweet.T) {
+	var calls []string
+	factory := func(name string) ServerMiddlewareFactory {
+		return func(config *ServerConfig) ([]Middleware, error) {
+			calls = append(calls, name)
+			return nil, nil
+		}
+	}
+
+	options := getBootOptions(RouteInitializerFunc(nil), []BootConfig{
+		WithDefaultServerMiddleware(factory("default")),
+		WithAdditionalServer("admin", RouteInitializerFunc(nil)),
+		WithAdditionalServer("internal", RouteInitializerFunc(nil), WithInitializerConfigs(WithServerMiddleware(factory("internal")))),
+	})
+
+	for _, server := range options.servers {
+		initializer := server.process(options.middlewareFactory).(*Server).initializer.(*ServerInitializer)
+		Expect(initializer.middlewareFactory).NotTo(BeNil())
+		initializer.middlewareFactory(&ServerConfig{})
+	}
+
+	Expect(calls).To(Equal([]string{"default", "default", "internal"}))
+}
+
+func (s *BootSuite) TestSetupFactoryDuplicateService(t sweet.T) {
+	setup := setupFactory(getBootOptions(RouteInitializerFunc(nil), []BootConfig{
+		WithService("db", "postgres"),
+		WithService("db", "mysql"),
+	}))
+
+	Expect(setup(nacelle.NewProcessContainer(), nacelle.NewServiceContainer())).NotTo(BeNil())
+}
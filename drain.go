@@ -0,0 +1,170 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+)
+
+type (
+	// InFlightRequests describes the requests being served by a router.
+	InFlightRequests struct {
+		// Total is the number of requests being served.
+		Total int `json:"total"`
+
+		// Routes is a map from URL patterns to the number of requests being
+		// served by the routes registered to that pattern. Patterns without
+		// requests in flight are omitted.
+		Routes map[string]int `json:"routes"`
+	}
+
+	// inFlightTracker counts the requests being served by a router and
+	// signals when the last request completes once the router is draining.
+	inFlightTracker struct {
+		mutex    sync.Mutex
+		total    int
+		routes   map[string]int
+		draining bool
+		idle     chan struct{}
+	}
+)
+
+func newInFlightTracker() *inFlightTracker {
+	return &inFlightTracker{
+		routes: map[string]int{},
+		idle:   make(chan struct{}),
+	}
+}
+
+// begin records a request to the given pattern. Returns false without
+// recording the request if the router is draining.
+func (t *inFlightTracker) begin(pattern string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.draining {
+		return false
+	}
+
+	t.total++
+	t.routes[pattern]++
+	return true
+}
+
+// end records the completion of a request to the given pattern.
+func (t *inFlightTracker) end(pattern string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.total--
+	if t.routes[pattern]--; t.routes[pattern] == 0 {
+		delete(t.routes, pattern)
+	}
+
+	if t.draining && t.total == 0 {
+		close(t.idle)
+	}
+}
+
+// drain rejects subsequent requests and blocks until there are no requests
+// in flight or the given context is canceled.
+func (t *inFlightTracker) drain(ctx context.Context) error {
+	t.mutex.Lock()
+	if !t.draining {
+		t.draining = true
+
+		if t.total == 0 {
+			close(t.idle)
+		}
+	}
+	t.mutex.Unlock()
+
+	// Prefer reporting success when the requests have already completed, as
+	// select chooses randomly between ready cases
+	select {
+	case <-t.idle:
+		return nil
+	default:
+	}
+
+	select {
+	case <-t.idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *inFlightTracker) snapshot() InFlightRequests {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	routes := make(map[string]int, len(t.routes))
+	for pattern, count := range t.routes {
+		routes[pattern] = count
+	}
+
+	return InFlightRequests{
+		Total:  t.total,
+		Routes: routes,
+	}
+}
+
+// trackInFlight wraps the handler registered to the given pattern so that
+// the requests it serves are counted. Once the router is draining, requests
+// are rejected by the drain handler instead.
+func (r *router) trackInFlight(pattern string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tracker := r.root.inFlight
+		if !tracker.begin(pattern) {
+			r.root.drainHandler.ServeHTTP(w, req)
+			return
+		}
+
+		defer tracker.end(pattern)
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// Drain marks the router as no longer ready and continues to serve requests
+// for the drain delay (see WithDrainDelay). It then responds to subsequent
+// requests with a 503-level response and blocks until the requests in flight
+// complete or the given context is canceled, in which case the context's error
+// is returned. If the context is canceled during the drain delay, requests are
+// rejected immediately. Requests to the health endpoints are served while
+// draining.
+func (r *router) Drain(ctx context.Context) error {
+	atomic.StoreInt32(&r.root.lifecycle, lifecycleStopping)
+
+	if delay := r.root.drainDelay; delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
+
+	return r.root.inFlight.drain(ctx)
+}
+
+// InFlight returns the number of requests currently being served by the
+// router, overall and by URL pattern.
+func (r *router) InFlight() InFlightRequests {
+	return r.root.inFlight.snapshot()
+}
+
+func makeDrainHandler(retryAfter time.Duration) Handler {
+	seconds := strconv.Itoa(int((retryAfter + time.Second - 1) / time.Second))
+
+	return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return DefaultResponse(ctx, req, http.StatusServiceUnavailable).SetHeader("Retry-After", seconds)
+	}
+}
@@ -0,0 +1,145 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type DrainSuite struct{}
+
+func (s *DrainSuite) TestInFlight(t sweet.T) {
+	router, release := newBlockingRouter()
+	Expect(router.InFlight()).To(Equal(InFlightRequests{Total: 0, Routes: map[string]int{}}))
+
+	done1 := serveAsync(router, "/users/1")
+	done2 := serveAsync(router, "/users/2")
+	done3 := serveAsync(router, "/teams")
+
+	Eventually(func() int { return router.InFlight().Total }).Should(Equal(3))
+	Expect(router.InFlight().Routes).To(Equal(map[string]int{"/users/{id}": 2, "/teams": 1}))
+
+	close(release)
+	Eventually(done1).Should(Receive(Equal(http.StatusOK)))
+	Eventually(done2).Should(Receive(Equal(http.StatusOK)))
+	Eventually(done3).Should(Receive(Equal(http.StatusOK)))
+	Expect(router.InFlight()).To(Equal(InFlightRequests{Total: 0, Routes: map[string]int{}}))
+}
+
+func (s *DrainSuite) TestDrain(t sweet.T) {
+	router, release := newBlockingRouter()
+	done := serveAsync(router, "/users/1")
+	Eventually(func() int { return router.InFlight().Total }).Should(Equal(1))
+
+	drained := make(chan error, 1)
+	go func() { drained <- router.Drain(context.Background()) }()
+
+	// New requests are rejected while the in-flight request completes
+	Eventually(func() int { return serveRequest(router, "GET", "/status", nil).Code }).Should(Equal(http.StatusServiceUnavailable))
+	recorder := serveRequest(router, "GET", "/users/2", nil)
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.HeaderMap.Get("Retry-After")).To(Equal("5"))
+	Consistently(drained).ShouldNot(Receive())
+
+	close(release)
+	Eventually(done).Should(Receive(Equal(http.StatusOK)))
+	Eventually(drained).Should(Receive(BeNil()))
+	Expect(router.InFlight().Total).To(Equal(0))
+}
+
+func (s *DrainSuite) TestDrainIdle(t sweet.T) {
+	router, _ := newBlockingRouter(WithDrainRetryAfter(time.Millisecond * 1500))
+	Expect(router.Drain(context.Background())).To(BeNil())
+	Expect(router.Drain(context.Background())).To(BeNil())
+
+	recorder := serveRequest(router, "GET", "/teams", nil)
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.HeaderMap.Get("Retry-After")).To(Equal("2"))
+}
+
+func (s *DrainSuite) TestDrainTimeout(t sweet.T) {
+	router, release := newBlockingRouter()
+	defer close(release)
+
+	serveAsync(router, "/users/1")
+	Eventually(func() int { return router.InFlight().Total }).Should(Equal(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	Expect(router.Drain(ctx)).To(Equal(context.DeadlineExceeded))
+	Expect(router.InFlight().Total).To(Equal(1))
+}
+
+func (s *DrainSuite) TestDrainDelay(t sweet.T) {
+	router, _ := newBlockingRouter(WithHealthEndpoints(), WithDrainDelay(time.Millisecond*200))
+	router.SetReady(true)
+
+	drained := make(chan error, 1)
+	go func() { drained <- router.Drain(context.Background()) }()
+
+	// Readiness fails before requests are rejected
+	Eventually(func() int { return serveRequest(router, "GET", "/readyz", nil).Code }).Should(Equal(http.StatusServiceUnavailable))
+	Expect(serveRequest(router, "GET", "/status", nil).Code).To(Equal(http.StatusOK))
+
+	Eventually(func() int { return serveRequest(router, "GET", "/status", nil).Code }).Should(Equal(http.StatusServiceUnavailable))
+	Eventually(drained).Should(Receive(BeNil()))
+}
+
+func (s *DrainSuite) TestDrainDelayCanceled(t sweet.T) {
+	router, _ := newBlockingRouter(WithDrainDelay(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	Expect(router.Drain(ctx)).To(BeNil())
+	Expect(serveRequest(router, "GET", "/status", nil).Code).To(Equal(http.StatusServiceUnavailable))
+}
+
+func (s *DrainSuite) TestDrainHealthEndpoints(t sweet.T) {
+	router, _ := newBlockingRouter(WithHealthEndpoints(), WithProblemDetails())
+	router.SetReady(true)
+	Expect(router.Drain(context.Background())).To(BeNil())
+
+	Expect(serveRequest(router, "GET", "/livez", nil).Code).To(Equal(http.StatusOK))
+
+	recorder := serveRequest(router, "GET", "/readyz", nil)
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"status": "stopping"}`))
+
+	recorder = serveRequest(router, "GET", "/teams", nil)
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal(ProblemContentType))
+	Expect(recorder.HeaderMap.Get("Retry-After")).To(Equal("5"))
+}
+
+//
+//
+
+func newBlockingRouter(configs ...RouterConfigFunc) (Router, chan struct{}) {
+	var (
+		router  = NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), configs...)
+		release = make(chan struct{})
+	)
+
+	handler := func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		<-release
+		return response.Empty(http.StatusOK)
+	}
+
+	router.MustRegister("/users/{id}", &EmptySpec{}, WithMethodHandler("GET", handler))
+	router.MustRegister("/teams", &EmptySpec{}, WithMethodHandler("GET", handler))
+	router.MustRegister("/status", &EmptySpec{}, WithMethodHandler("GET", makeEmptyHandler(http.StatusOK)))
+	return router, release
+}
+
+func serveAsync(router Router, url string) <-chan int {
+	done := make(chan int, 1)
+	go func() { done <- serveRequest(router, "GET", url, nil).Code }()
+	return done
+}
@@ -0,0 +1,112 @@
+package chevron
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+)
+
+type (
+	// HandlerE converts an HTTP request into a response object or an error.
+	// Errors are converted into a response by the router's error mapper.
+	HandlerE func(context.Context, *http.Request, nacelle.Logger) (response.Response, error)
+
+	// ErrorMapper converts an error returned from a HandlerE into a response
+	// object.
+	ErrorMapper func(context.Context, *http.Request, nacelle.Logger, error) response.Response
+
+	// ErrorBody is the JSON body of a response created by the default error
+	// mapper.
+	ErrorBody struct {
+		Status  int               `json:"status"`
+		Message string            `json:"message"`
+		Fields  map[string]string `json:"fields,omitempty"`
+	}
+
+	// fieldErrors is implemented by errors which describe a set of invalid
+	// fields of a request.
+	fieldErrors interface {
+		FieldErrors() map[string]string
+	}
+
+	tokenErrorMapper string
+)
+
+// TokenErrorMapper is the unique token to which the router's error mapper
+// is written to the request context.
+var TokenErrorMapper = tokenErrorMapper("chevron.error_mapper")
+
+// Handle invokes the wrapped function. If the function returns an error, it
+// is converted into a response by the error mapper registered to the given
+// context. This method conforms to the Handler signature, which allows it to
+// be used within the methods of a ResourceSpec.
+func (f HandlerE) Handle(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	resp, err := f(ctx, req, logger)
+	if err != nil {
+		return GetErrorMapper(ctx)(ctx, req, logger, err)
+	}
+
+	return resp
+}
+
+// GetErrorMapper retrieves the router's error mapper from the given context.
+// If no error mapper is registered with this context, the default error mapper
+// is returned.
+func GetErrorMapper(ctx context.Context) ErrorMapper {
+	if val, ok := ctx.Value(TokenErrorMapper).(ErrorMapper); ok {
+		return val
+	}
+
+	return DefaultErrorMapper
+}
+
+func setErrorMapper(ctx context.Context, mapper ErrorMapper) context.Context {
+	return context.WithValue(ctx, TokenErrorMapper, mapper)
+}
+
+// DefaultErrorMapper converts errors conforming to StatusError (including
+// wrapped errors) into a JSON response with the error's status code. The
+// invalid fields of a ValidationError or BindError are included. All
+// other errors are logged and converted into a 500-level response which
+// does not disclose the error. If the router is configured to use problem
+// details, the message and fields are written to the detail and `fields`
+// members of the problem.
+func DefaultErrorMapper(ctx context.Context, req *http.Request, logger nacelle.Logger, err error) response.Response {
+	var statusErr StatusError
+	if !errors.As(err, &statusErr) {
+		logger.Error("Request handler returned an unexpected error (%s)", err.Error())
+		return errorResponse(ctx, req, http.StatusInternalServerError, "", nil)
+	}
+
+	var fields map[string]string
+	var fieldErr fieldErrors
+	if errors.As(err, &fieldErr) {
+		fields = fieldErr.FieldErrors()
+	}
+
+	return errorResponse(ctx, req, statusErr.StatusCode(), statusErr.Error(), fields)
+}
+
+func errorResponse(ctx context.Context, req *http.Request, status int, message string, fields map[string]string) response.Response {
+	if options, ok := getProblemOptions(ctx); ok {
+		var extensions map[string]interface{}
+		if len(fields) > 0 {
+			extensions = map[string]interface{}{"fields": fields}
+		}
+
+		return options.response(req, status, message, extensions)
+	}
+
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	return response.JSON(ErrorBody{
+		Status:  status,
+		Message: message,
+		Fields:  fields,
+	}).SetStatusCode(status)
+}
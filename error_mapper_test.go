@@ -0,0 +1,131 @@
+package chevron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type ErrorMapperSuite struct{}
+
+func (s *ErrorMapperSuite) TestTypedErrors(t sweet.T) {
+	testCases := []struct {
+		err    error
+		status int
+		body   string
+	}{
+		{NewBadRequestError("bad %s", "input"), http.StatusBadRequest, `{"status": 400, "message": "bad input"}`},
+		{NewUnauthorizedError("no token"), http.StatusUnauthorized, `{"status": 401, "message": "no token"}`},
+		{NewForbiddenError("nope"), http.StatusForbidden, `{"status": 403, "message": "nope"}`},
+		{NewNotFoundError("user %d not found", 3), http.StatusNotFound, `{"status": 404, "message": "user 3 not found"}`},
+		{NewConflictError("exists"), http.StatusConflict, `{"status": 409, "message": "exists"}`},
+		{fmt.Errorf("lookup: %w", NewNotFoundError("missing")), http.StatusNotFound, `{"status": 404, "message": "missing"}`},
+		{
+			NewValidationError("invalid user", map[string]string{"name": "required"}),
+			http.StatusUnprocessableEntity,
+			`{"status": 422, "message": "invalid user", "fields": {"name": "required"}}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		var (
+			container = nacelle.NewServiceContainer()
+			logger    = &countingLogger{Logger: nacelle.NewNilLogger()}
+			router    = NewRouter(container, logger)
+			err       = testCase.err
+		)
+
+		Expect(router.Register("/users", &HandlerSpec{handler: HandlerE(func(ctx context.Context, req *http.Request, logger nacelle.Logger) (response.Response, error) {
+			return nil, err
+		}).Handle})).To(BeNil())
+
+		req, _ := http.NewRequest("GET", "/users", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		Expect(recorder.Code).To(Equal(testCase.status))
+		Expect(recorder.Body.String()).To(MatchJSON(testCase.body))
+		Expect(logger.errors).To(Equal(0))
+	}
+}
+
+func (s *ErrorMapperSuite) TestUnexpectedError(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = &countingLogger{Logger: nacelle.NewNilLogger()}
+		router    = NewRouter(container, logger)
+	)
+
+	Expect(router.Register("/users", &HandlerSpec{handler: HandlerE(func(ctx context.Context, req *http.Request, logger nacelle.Logger) (response.Response, error) {
+		return nil, errors.New("database is on fire")
+	}).Handle})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"status": 500, "message": "Internal Server Error"}`))
+	Expect(recorder.Body.String()).NotTo(ContainSubstring("fire"))
+	Expect(logger.errors).To(Equal(1))
+}
+
+func (s *ErrorMapperSuite) TestNoError(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	Expect(router.Register("/users", &HandlerSpec{handler: HandlerE(func(ctx context.Context, req *http.Request, logger nacelle.Logger) (response.Response, error) {
+		return response.JSON([]string{"a", "b"}), nil
+	}).Handle})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`["a", "b"]`))
+}
+
+func (s *ErrorMapperSuite) TestWithErrorMapper(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		mapped    error
+	)
+
+	mapper := func(ctx context.Context, req *http.Request, logger nacelle.Logger, err error) response.Response {
+		mapped = err
+		return response.Empty(http.StatusTeapot)
+	}
+
+	router := NewRouter(container, logger, WithErrorMapper(mapper))
+
+	Expect(router.Register("/users", &HandlerSpec{handler: HandlerE(func(ctx context.Context, req *http.Request, logger nacelle.Logger) (response.Response, error) {
+		return nil, NewNotFoundError("missing")
+	}).Handle})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusTeapot))
+	Expect(mapped).To(MatchError("missing"))
+}
+
+//
+//
+
+type countingLogger struct {
+	nacelle.Logger
+	errors int
+}
+
+func (l *countingLogger) Error(format string, args ...interface{}) {
+	l.errors++
+}
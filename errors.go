@@ -0,0 +1,138 @@
+package chevron
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type (
+	// StatusError is an error that corresponds to an HTTP status code. Errors
+	// conforming to this interface (including wrapped errors) are converted into
+	// a response with that status code by the default error mapper.
+	StatusError interface {
+		error
+
+		// StatusCode returns the HTTP status code of the error.
+		StatusCode() int
+	}
+
+	// BadRequestError indicates that the request was malformed.
+	BadRequestError struct{ Message string }
+
+	// UnauthorizedError indicates that the request lacks valid credentials.
+	UnauthorizedError struct{ Message string }
+
+	// ForbiddenError indicates that the requester may not access the resource.
+	ForbiddenError struct{ Message string }
+
+	// NotFoundError indicates that the requested entity does not exist.
+	NotFoundError struct{ Message string }
+
+	// ConflictError indicates that the request conflicts with the current state
+	// of the resource.
+	ConflictError struct{ Message string }
+
+	// ValidationError indicates that the request was well-formed but contained
+	// invalid values. Fields maps the names of invalid fields to a description
+	// of the problem.
+	ValidationError struct {
+		Message string
+		Fields  map[string]string
+	}
+)
+
+// NewBadRequestError creates a BadRequestError with a formatted message.
+func NewBadRequestError(format string, args ...interface{}) *BadRequestError {
+	return &BadRequestError{Message: fmt.Sprintf(format, args...)}
+}
+
+// NewUnauthorizedError creates an UnauthorizedError with a formatted message.
+func NewUnauthorizedError(format string, args ...interface{}) *UnauthorizedError {
+	return &UnauthorizedError{Message: fmt.Sprintf(format, args...)}
+}
+
+// NewForbiddenError creates a ForbiddenError with a formatted message.
+func NewForbiddenError(format string, args ...interface{}) *ForbiddenError {
+	return &ForbiddenError{Message: fmt.Sprintf(format, args...)}
+}
+
+// NewNotFoundError creates a NotFoundError with a formatted message.
+func NewNotFoundError(format string, args ...interface{}) *NotFoundError {
+	return &NotFoundError{Message: fmt.Sprintf(format, args...)}
+}
+
+// NewConflictError creates a ConflictError with a formatted message.
+func NewConflictError(format string, args ...interface{}) *ConflictError {
+	return &ConflictError{Message: fmt.Sprintf(format, args...)}
+}
+
+// NewValidationError creates a ValidationError with the given invalid fields.
+func NewValidationError(message string, fields map[string]string) *ValidationError {
+	return &ValidationError{Message: message, Fields: fields}
+}
+
+// Error returns the error's message.
+func (e *BadRequestError) Error() string {
+	return e.Message
+}
+
+// StatusCode returns 400.
+func (e *BadRequestError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// Error returns the error's message.
+func (e *UnauthorizedError) Error() string {
+	return e.Message
+}
+
+// StatusCode returns 401.
+func (e *UnauthorizedError) StatusCode() int {
+	return http.StatusUnauthorized
+}
+
+// Error returns the error's message.
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+// StatusCode returns 403.
+func (e *ForbiddenError) StatusCode() int {
+	return http.StatusForbidden
+}
+
+// Error returns the error's message.
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+// StatusCode returns 404.
+func (e *NotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
+
+// Error returns the error's message.
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// StatusCode returns 409.
+func (e *ConflictError) StatusCode() int {
+	return http.StatusConflict
+}
+
+// Error returns the error's message.
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// StatusCode returns 422.
+func (e *ValidationError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// FieldErrors returns the names of the invalid fields mapped to a description
+// of the problem.
+func (e *ValidationError) FieldErrors() map[string]string {
+	return e.Fields
+}
@@ -33,5 +33,5 @@ func setupRoutes(config nacelle.Config, router chevron.Router) error {
 }
 
 func main() {
-	chevron.BootAndExit("app", chevron.RouteInitializerFunc(setupRoutes))
+	middleware.BootAndExit("app", chevron.RouteInitializerFunc(setupRoutes))
 }
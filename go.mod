@@ -1,20 +1,80 @@
 module github.com/go-nacelle/chevron
 
+go 1.18
+
 require (
 	github.com/aphistic/sweet v0.2.0
 	github.com/aphistic/sweet-junit v0.0.0-20190314030539-8d7e248096c2
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/efritz/gache v0.0.0-20181229204852-821b2f6c80a7
 	github.com/efritz/glock v0.0.0-20181228234553-f184d69dff2c
-	github.com/efritz/overcurrent v0.0.0-20181228234627-ab9925562a09 // indirect
 	github.com/efritz/response v0.0.0-20181228234645-82af2456949a
 	github.com/ghodss/yaml v1.0.0
+	github.com/go-nacelle/config v1.0.0
 	github.com/go-nacelle/httpbase v1.0.0
 	github.com/go-nacelle/nacelle v1.0.0
 	github.com/google/uuid v1.1.1
 	github.com/gorilla/mux v1.7.2
 	github.com/onsi/gomega v1.5.0
+	github.com/vmihailenco/msgpack v4.0.4+incompatible
+	github.com/xeipuuv/gojsonschema v1.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/BurntSushi/toml v0.3.1 // indirect
+	github.com/alecthomas/kingpin v2.2.6+incompatible // indirect
+	github.com/alecthomas/template v0.0.0-20160405071501-a0175ee3bccc // indirect
+	github.com/alecthomas/units v0.0.0-20151022065526-2efee857e7cf // indirect
+	github.com/aphistic/golf v0.0.0-20180712155816-02c07f170c5a // indirect
+	github.com/aphistic/gomol v0.0.0-20190314031446-1546845ba714 // indirect
+	github.com/aphistic/gomol-console v0.0.0-20180111152223-9fa1742697a8 // indirect
+	github.com/aphistic/gomol-gelf v0.0.0-20170516042314-573e82a82082 // indirect
+	github.com/aphistic/gomol-json v1.1.0 // indirect
+	github.com/bradhe/stopwatch v0.0.0-20180424000511-fd55e776a960 // indirect
+	github.com/dave/jennifer v1.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dustin/go-humanize v1.0.0 // indirect
+	github.com/efritz/backoff v1.0.0 // indirect
+	github.com/efritz/deepjoy v0.0.0-20181228234358-6d5e5c61e1b3 // indirect
+	github.com/efritz/go-genlib v0.0.0-20190429143346-e1e478a98211 // indirect
+	github.com/efritz/go-mockgen v0.0.0-20190613153341-3425cf558834 // indirect
+	github.com/efritz/overcurrent v0.0.0-20181228234627-ab9925562a09 // indirect
+	github.com/efritz/sse v0.0.0-20181115162819-b93a5a07589b // indirect
+	github.com/efritz/watchdog v0.0.0-20181228234521-84cf7cb74656 // indirect
+	github.com/fatih/structtag v1.0.0 // indirect
+	github.com/fsnotify/fsnotify v1.4.7 // indirect
+	github.com/garyburd/redigo v1.6.0 // indirect
+	github.com/go-nacelle/log v1.0.0 // indirect
+	github.com/go-nacelle/process v1.0.0 // indirect
+	github.com/go-nacelle/service v1.0.0 // indirect
+	github.com/golang/protobuf v1.3.1 // indirect
+	github.com/gomodule/redigo v2.0.0+incompatible // indirect
+	github.com/hpcloud/tail v1.0.0 // indirect
+	github.com/kr/pretty v0.1.0 // indirect
+	github.com/kr/pty v1.1.5 // indirect
+	github.com/kr/text v0.1.0 // indirect
+	github.com/mattn/go-colorable v0.1.2 // indirect
+	github.com/mattn/go-isatty v0.0.8 // indirect
+	github.com/mattn/go-zglob v0.0.1 // indirect
+	github.com/mgutz/ansi v0.0.0-20170206155736-9520e82c474b // indirect
+	github.com/mitchellh/go-wordwrap v1.0.0 // indirect
+	github.com/onsi/ginkgo v1.8.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/sergi/go-diff v1.0.0 // indirect
+	github.com/spaolacci/murmur3 v1.1.0 // indirect
+	github.com/stretchr/objx v0.2.0 // indirect
+	github.com/stretchr/testify v1.3.0 // indirect
 	github.com/xeipuuv/gojsonpointer v0.0.0-20180127040702-4e3ac2762d5f // indirect
 	github.com/xeipuuv/gojsonreference v0.0.0-20180127040603-bd5ef7bd5415 // indirect
-	github.com/xeipuuv/gojsonschema v1.1.0
+	golang.org/x/crypto v0.0.0-20190617133340-57b3e21c3d56 // indirect
+	golang.org/x/net v0.0.0-20190613194153-d28f0bde5980 // indirect
+	golang.org/x/sync v0.0.0-20190423024810-112230192c58 // indirect
+	golang.org/x/sys v0.0.0-20190616124812-15dcb6c0061f // indirect
+	golang.org/x/text v0.3.2 // indirect
+	golang.org/x/tools v0.0.0-20190617190820-da514acc4774 // indirect
+	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
+	gopkg.in/fsnotify.v1 v1.4.7 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
 )
@@ -0,0 +1,258 @@
+package chevron
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+)
+
+type (
+	// HealthCheck reports whether a dependency of the server (e.g. a database
+	// connection) is healthy.
+	HealthCheck interface {
+		// Check returns a non-nil error if the dependency is unhealthy. The
+		// context is canceled once the check's timeout elapses.
+		Check(ctx context.Context) error
+	}
+
+	// HealthCheckFunc is a function conforming to the HealthCheck interface.
+	HealthCheckFunc func(ctx context.Context) error
+
+	// HealthChecks is the set of health checks consulted by the readiness
+	// endpoint of a router configured with WithHealthEndpoints. The set is
+	// shared via the service container (see GetHealthChecks).
+	HealthChecks struct {
+		mutex  sync.RWMutex
+		checks []*healthCheck
+	}
+
+	// HealthCheckConfigFunc is a function used to configure a health check.
+	HealthCheckConfigFunc func(*healthCheck)
+
+	healthCheck struct {
+		name    string
+		check   HealthCheck
+		timeout time.Duration
+	}
+
+	// HealthReport is the body of a response from the liveness and readiness
+	// endpoints.
+	HealthReport struct {
+		Status string                       `json:"status"`
+		Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+	}
+
+	// HealthCheckResult is the outcome of a single health check.
+	HealthCheckResult struct {
+		Status   string `json:"status"`
+		Error    string `json:"error,omitempty"`
+		Duration string `json:"duration"`
+	}
+
+	livenessSpec struct {
+		*EmptySpec
+	}
+
+	readinessSpec struct {
+		*EmptySpec
+		router  *router
+		timeout time.Duration
+	}
+)
+
+// HealthChecksServiceName is the name of the service to which the set of
+// health checks is registered.
+const HealthChecksServiceName = "chevron-health-checks"
+
+const (
+	lifecycleStarting int32 = iota
+	lifecycleReady
+	lifecycleStopping
+)
+
+const (
+	healthStatusOK       = "ok"
+	healthStatusFailing  = "failing"
+	healthStatusStarting = "starting"
+	healthStatusStopping = "stopping"
+)
+
+var healthChecksLock sync.Mutex
+
+// Check calls the wrapped function.
+func (f HealthCheckFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// WithCheckTimeout sets the duration after which the health check is
+// considered failed. This overrides the timeout set by WithHealthTimeout.
+func WithCheckTimeout(timeout time.Duration) HealthCheckConfigFunc {
+	return func(c *healthCheck) { c.timeout = timeout }
+}
+
+// GetHealthChecks retrieves the set of health checks from the service
+// container. If the set has not yet been registered, an empty set is
+// registered and returned. This allows initializers to register health
+// checks regardless of whether they run before or after the router is
+// created.
+func GetHealthChecks(services nacelle.ServiceContainer) (*HealthChecks, error) {
+	healthChecksLock.Lock()
+	defer healthChecksLock.Unlock()
+
+	if value, err := services.Get(HealthChecksServiceName); err == nil {
+		checks, ok := value.(*HealthChecks)
+		if !ok {
+			return nil, fmt.Errorf("service `%s` is not a set of health checks", HealthChecksServiceName)
+		}
+
+		return checks, nil
+	}
+
+	checks := &HealthChecks{}
+	if err := services.Set(HealthChecksServiceName, checks); err != nil {
+		return nil, err
+	}
+
+	return checks, nil
+}
+
+// Register adds a health check with the given name. It is an error to
+// register the same name twice.
+func (c *HealthChecks) Register(name string, check HealthCheck, configs ...HealthCheckConfigFunc) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, existing := range c.checks {
+		if existing.name == name {
+			return fmt.Errorf("health check already registered with name `%s`", name)
+		}
+	}
+
+	registered := &healthCheck{name: name, check: check}
+	for _, f := range configs {
+		f(registered)
+	}
+
+	c.checks = append(c.checks, registered)
+	return nil
+}
+
+// run invokes every health check concurrently. Checks without an explicit
+// timeout use the given default.
+func (c *HealthChecks) run(ctx context.Context, timeout time.Duration) map[string]HealthCheckResult {
+	c.mutex.RLock()
+	checks := append([]*healthCheck{}, c.checks...)
+	c.mutex.RUnlock()
+
+	var (
+		results = make(map[string]HealthCheckResult, len(checks))
+		lock    sync.Mutex
+		wg      sync.WaitGroup
+	)
+
+	for _, check := range checks {
+		wg.Add(1)
+
+		go func(check *healthCheck) {
+			defer wg.Done()
+
+			checkTimeout := timeout
+			if check.timeout > 0 {
+				checkTimeout = check.timeout
+			}
+
+			result := check.run(ctx, checkTimeout)
+
+			lock.Lock()
+			results[check.name] = result
+			lock.Unlock()
+		}(check)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func (c *healthCheck) run(ctx context.Context, timeout time.Duration) HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		started = time.Now()
+		errs    = make(chan error, 1)
+	)
+
+	go func() { errs <- c.check.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = fmt.Errorf("health check did not complete within %s", timeout)
+	}
+
+	result := HealthCheckResult{
+		Status:   healthStatusOK,
+		Duration: time.Since(started).String(),
+	}
+
+	if err != nil {
+		result.Status = healthStatusFailing
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Get reports that the server is alive.
+func (s *livenessSpec) Get(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return healthResponse(http.StatusOK, HealthReport{Status: healthStatusOK})
+}
+
+// Get reports whether the server is ready to serve traffic. The server is not
+// ready before it is initialized, after shutdown begins, or while any of the
+// registered health checks fail.
+func (s *readinessSpec) Get(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	switch atomic.LoadInt32(&s.router.root.lifecycle) {
+	case lifecycleStarting:
+		return healthResponse(http.StatusServiceUnavailable, HealthReport{Status: healthStatusStarting})
+	case lifecycleStopping:
+		return healthResponse(http.StatusServiceUnavailable, HealthReport{Status: healthStatusStopping})
+	}
+
+	checks, err := GetHealthChecks(s.router.services)
+	if err != nil {
+		logger.Error("Failed to retrieve health checks (%s)", err.Error())
+		return DefaultResponse(ctx, req, http.StatusInternalServerError)
+	}
+
+	report := HealthReport{
+		Status: healthStatusOK,
+		Checks: checks.run(ctx, s.timeout),
+	}
+
+	for name, result := range report.Checks {
+		if result.Status != healthStatusOK {
+			logger.Warning("Health check %s failed (%s)", name, result.Error)
+			report.Status = healthStatusFailing
+		}
+	}
+
+	if report.Status != healthStatusOK {
+		return healthResponse(http.StatusServiceUnavailable, report)
+	}
+
+	return healthResponse(http.StatusOK, report)
+}
+
+func healthResponse(status int, report HealthReport) response.Response {
+	return response.JSON(report).
+		SetStatusCode(status).
+		SetHeader("Cache-Control", "no-store")
+}
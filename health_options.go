@@ -0,0 +1,32 @@
+package chevron
+
+import "time"
+
+type (
+	// HealthConfigFunc is a function used to configure the health endpoints.
+	HealthConfigFunc func(*healthOptions)
+
+	healthOptions struct {
+		livenessPath  string
+		readinessPath string
+		timeout       time.Duration
+	}
+)
+
+// WithLivenessPath sets the URL pattern of the liveness endpoint. The
+// default is `/livez`.
+func WithLivenessPath(path string) HealthConfigFunc {
+	return func(o *healthOptions) { o.livenessPath = path }
+}
+
+// WithReadinessPath sets the URL pattern of the readiness endpoint. The
+// default is `/readyz`.
+func WithReadinessPath(path string) HealthConfigFunc {
+	return func(o *healthOptions) { o.readinessPath = path }
+}
+
+// WithHealthTimeout sets the duration after which a health check without
+// its own timeout is considered failed. The default is one second.
+func WithHealthTimeout(timeout time.Duration) HealthConfigFunc {
+	return func(o *healthOptions) { o.timeout = timeout }
+}
@@ -0,0 +1,141 @@
+package chevron
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type HealthSuite struct{}
+
+func (s *HealthSuite) TestLiveness(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithHealthEndpoints())
+
+	recorder := serveRequest(router, "GET", "/livez", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"status": "ok"}`))
+	Expect(recorder.HeaderMap.Get("Cache-Control")).To(Equal("no-store"))
+}
+
+func (s *HealthSuite) TestReadinessLifecycle(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithHealthEndpoints())
+
+	// Starting
+	recorder := serveRequest(router, "GET", "/readyz", nil)
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"status": "starting"}`))
+
+	// Not ready before ever being ready
+	router.SetReady(false)
+	Expect(serveRequest(router, "GET", "/readyz", nil).Body.String()).To(MatchJSON(`{"status": "starting"}`))
+
+	// Ready
+	router.SetReady(true)
+	recorder = serveRequest(router, "GET", "/readyz", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"status": "ok"}`))
+
+	// Stopping
+	router.SetReady(false)
+	recorder = serveRequest(router, "GET", "/readyz", nil)
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"status": "stopping"}`))
+
+	// Liveness is unaffected
+	Expect(serveRequest(router, "GET", "/livez", nil).Code).To(Equal(http.StatusOK))
+}
+
+func (s *HealthSuite) TestReadinessChecks(t sweet.T) {
+	services := nacelle.NewServiceContainer()
+	checks, err := GetHealthChecks(services)
+	Expect(err).To(BeNil())
+
+	Expect(checks.Register("db", HealthCheckFunc(func(ctx context.Context) error { return nil }))).To(BeNil())
+
+	router := NewRouter(services, nacelle.NewNilLogger(), WithHealthEndpoints(WithReadinessPath("/ready")))
+	router.SetReady(true)
+
+	recorder := serveRequest(router, "GET", "/ready", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(ContainSubstring(`"db":{"status":"ok"`))
+
+	// Checks registered after the router is created are also consulted
+	Expect(checks.Register("cache", HealthCheckFunc(func(ctx context.Context) error {
+		return fmt.Errorf("connection refused")
+	}))).To(BeNil())
+
+	recorder = serveRequest(router, "GET", "/ready", nil)
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.Body.String()).To(ContainSubstring(`"status":"failing"`))
+	Expect(recorder.Body.String()).To(ContainSubstring(`"db":{"status":"ok"`))
+	Expect(recorder.Body.String()).To(ContainSubstring(`"cache":{"status":"failing","error":"connection refused"`))
+}
+
+func (s *HealthSuite) TestReadinessCheckTimeout(t sweet.T) {
+	var (
+		services = nacelle.NewServiceContainer()
+		canceled = make(chan struct{})
+	)
+
+	checks, err := GetHealthChecks(services)
+	Expect(err).To(BeNil())
+
+	slow := HealthCheckFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		close(canceled)
+		return nil
+	})
+
+	fast := HealthCheckFunc(func(ctx context.Context) error {
+		select {
+		case <-time.After(time.Millisecond * 20):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	Expect(checks.Register("slow", slow, WithCheckTimeout(time.Millisecond*10))).To(BeNil())
+	Expect(checks.Register("fast", fast)).To(BeNil())
+
+	router := NewRouter(services, nacelle.NewNilLogger(), WithHealthEndpoints(WithHealthTimeout(time.Second)))
+	router.SetReady(true)
+
+	recorder := serveRequest(router, "GET", "/readyz", nil)
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.Body.String()).To(ContainSubstring(`"slow":{"status":"failing","error":"health check did not complete within 10ms"`))
+	Expect(recorder.Body.String()).To(ContainSubstring(`"fast":{"status":"ok"`))
+	Eventually(canceled).Should(BeClosed())
+}
+
+func (s *HealthSuite) TestGetHealthChecks(t sweet.T) {
+	services := nacelle.NewServiceContainer()
+
+	checks1, err := GetHealthChecks(services)
+	Expect(err).To(BeNil())
+	checks2, err := GetHealthChecks(services)
+	Expect(err).To(BeNil())
+	Expect(checks1).To(BeIdenticalTo(checks2))
+
+	Expect(checks1.Register("db", HealthCheckFunc(nil))).To(BeNil())
+	Expect(checks1.Register("db", HealthCheckFunc(nil))).To(MatchError("health check already registered with name `db`"))
+
+	services = nacelle.NewServiceContainer()
+	Expect(services.Set(HealthChecksServiceName, "invalid")).To(BeNil())
+	_, err = GetHealthChecks(services)
+	Expect(err).To(MatchError("service `chevron-health-checks` is not a set of health checks"))
+}
+
+func (s *HealthSuite) TestInitializerMarksReady(t sweet.T) {
+	server := &http.Server{}
+	Expect(initServer(nil, server, WithHealthEndpoints())).To(BeNil())
+	Expect(serveRequest(server.Handler, "GET", "/readyz", nil).Code).To(Equal(http.StatusOK))
+
+	Expect(server.Shutdown(context.Background())).To(BeNil())
+	Eventually(func() int { return serveRequest(server.Handler, "GET", "/readyz", nil).Code }).Should(Equal(http.StatusServiceUnavailable))
+}
@@ -1,7 +1,10 @@
 package chevron
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-nacelle/httpbase"
 	"github.com/go-nacelle/nacelle"
@@ -10,10 +13,15 @@ import (
 type (
 	// ServerInitializer implements httpbase.ServerInitializer.
 	ServerInitializer struct {
-		Services    nacelle.ServiceContainer `service:"services"`
-		Logger      nacelle.Logger           `service:"logger"`
-		initializer RouteInitializer
-		configs     []RouterConfigFunc
+		Services          nacelle.ServiceContainer `service:"services"`
+		Logger            nacelle.Logger           `service:"logger"`
+		initializer       RouteInitializer
+		configs           []RouterConfigFunc
+		middlewareFactory ServerMiddlewareFactory
+		tagModifiers      []nacelle.TagModifier
+		router            Router
+		drainTimeout      time.Duration
+		drainDelay        time.Duration
 	}
 
 	// RouteInitializer initializes a Router instance.
@@ -25,6 +33,15 @@ type (
 	// RouteInitializerFunc is a function conforming to the RouteInitializer
 	// interface.
 	RouteInitializerFunc func(config nacelle.Config, router Router) error
+
+	// InitializerConfig configures a ServerInitializer. RouterConfigFunc
+	// values are also accepted, and are applied to the initializer's router.
+	InitializerConfig interface {
+		applyInitializer(*ServerInitializer)
+	}
+
+	// InitializerConfigFunc is a function used to configure a ServerInitializer.
+	InitializerConfigFunc func(*ServerInitializer)
 )
 
 // Init calls the wrapped function.
@@ -32,21 +49,118 @@ func (f RouteInitializerFunc) Init(config nacelle.Config, router Router) error {
 	return f(config, router)
 }
 
-// NewInitializer creates a new ServerInitializer.
-func NewInitializer(initializer RouteInitializer, configs ...RouterConfigFunc) httpbase.ServerInitializer {
-	return &ServerInitializer{
+// applyInitializer calls the wrapped function with the given initializer.
+func (f InitializerConfigFunc) applyInitializer(i *ServerInitializer) {
+	f(i)
+}
+
+// applyInitializer adds the router config to the configs applied to the
+// router created by the given initializer.
+func (f RouterConfigFunc) applyInitializer(i *ServerInitializer) {
+	i.configs = append(i.configs, f)
+}
+
+// WithServerMiddleware sets the factory that creates the middleware enabled
+// by the server config. The middleware package supplies a factory for the
+// built-in middleware (see middleware.FromServerConfig), and a constructor
+// which registers it (see middleware.NewInitializer).
+func WithServerMiddleware(factory ServerMiddlewareFactory) InitializerConfigFunc {
+	return func(i *ServerInitializer) { i.middlewareFactory = factory }
+}
+
+// WithConfigTagModifiers applies the given tag modifiers when loading the
+// server config. This allows several servers in the same process to read
+// distinct settings (e.g. via nacelle.NewEnvTagPrefixer).
+func WithConfigTagModifiers(modifiers ...nacelle.TagModifier) InitializerConfigFunc {
+	return func(i *ServerInitializer) { i.tagModifiers = append(i.tagModifiers, modifiers...) }
+}
+
+// NewInitializer creates a new ServerInitializer. The router is drained
+// before shutdown only if the initializer is supplied to NewServer (which
+// BootAndExit does). A server created by passing the initializer directly to
+// httpbase.NewServer marks the router as no longer ready once it begins to
+// shut down, but does not drain the router.
+func NewInitializer(initializer RouteInitializer, configs ...InitializerConfig) httpbase.ServerInitializer {
+	i := &ServerInitializer{
 		initializer: initializer,
-		configs:     configs,
 	}
+
+	for _, config := range configs {
+		config.applyInitializer(i)
+	}
+
+	return i
 }
 
-// Init creates a router which becomes the server's handler and calls the
-// attached route initializer.
+// Init loads a ServerConfig, creates a router which becomes the server's
+// handler, and calls the attached route initializer. Router configs supplied
+// to NewInitializer take precedence over the values of the server config.
+// The middleware enabled by the server config wraps the router's dispatch
+// pipeline. If middleware is enabled but no middleware factory is registered,
+// an error is returned.
+// The router is marked ready once the route initializer completes, and is
+// marked no longer ready once the server begins to shut down.
 func (i *ServerInitializer) Init(config nacelle.Config, server *http.Server) error {
-	configs := append([]RouterConfigFunc{WithLogger(i.Logger)}, i.configs...)
+	serverConfig := &ServerConfig{}
+	if err := config.Load(serverConfig, i.tagModifiers...); err != nil {
+		return err
+	}
+
+	configs := append([]RouterConfigFunc{WithLogger(i.Logger)}, serverConfig.routerConfigs()...)
+	configs = append(configs, i.configs...)
 
-	// TODO - control additional configs with env vars
 	router := NewRouter(i.Services, i.Logger, configs...)
+
+	if serverConfig.MiddlewareEnabled() {
+		middleware, err := i.serverMiddleware(serverConfig)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range middleware {
+			if err := router.Use(m); err != nil {
+				return err
+			}
+		}
+	}
+
 	server.Handler = router
-	return i.initializer.Init(config, router)
+	i.router = router
+	i.drainTimeout = serverConfig.DrainTimeout
+	i.drainDelay = serverConfig.DrainDelay
+
+	if err := i.initializer.Init(config, router); err != nil {
+		return err
+	}
+
+	router.SetReady(true)
+	server.RegisterOnShutdown(func() { router.SetReady(false) })
+	return nil
+}
+
+// serverMiddleware creates the middleware enabled by the given server config.
+func (i *ServerInitializer) serverMiddleware(config *ServerConfig) ([]Middleware, error) {
+	if i.middlewareFactory == nil {
+		return nil, fmt.Errorf("server config enables middleware, but no middleware factory is registered (use middleware.NewInitializer or WithServerMiddleware)")
+	}
+
+	return i.middlewareFactory(config)
+}
+
+// drain drains the initialized router, if any. The requests in flight once
+// the drain delay of the server config elapses must complete within the
+// drain timeout of the server config.
+func (i *ServerInitializer) drain() {
+	if i.router == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), i.drainDelay+i.drainTimeout)
+	defer cancel()
+
+	i.Logger.Info("Draining in-flight requests")
+
+	if err := i.router.Drain(ctx); err != nil {
+		i.Logger.Warning("Shutting down with %d requests in flight", i.router.InFlight().Total)
+	}
 }
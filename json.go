@@ -0,0 +1,118 @@
+package chevron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+)
+
+type (
+	// JSONConfigFunc is a function used to configure a typed JSON handler.
+	JSONConfigFunc func(*jsonOptions)
+
+	jsonOptions struct {
+		status                int
+		disallowUnknownFields bool
+	}
+)
+
+// WithJSONStatus sets the status code of a successful response. By
+// default, successful responses have a 200 status code.
+func WithJSONStatus(status int) JSONConfigFunc {
+	return func(o *jsonOptions) { o.status = status }
+}
+
+// WithDisallowUnknownFields causes request bodies containing keys
+// which do not match a field of the request type to be rejected.
+func WithDisallowUnknownFields() JSONConfigFunc {
+	return func(o *jsonOptions) { o.disallowUnknownFields = true }
+}
+
+// JSON creates a handler from a function operating on typed request and
+// response values. The request body is decoded into a value of type Req.
+// An empty body decodes to the zero value of Req. A body with a non-JSON
+// content type is rejected with a 415 response, and a body which cannot be
+// decoded into Req is passed to the router's bad request handler. The value
+// returned by the function is encoded as the JSON body of the response; a
+// value which cannot be encoded results in a 500 response. An error returned
+// by the function is passed to the router's error mapper.
+func JSON[Req, Resp any](f func(context.Context, Req, nacelle.Logger) (Resp, error), configs ...JSONConfigFunc) Handler {
+	options := &jsonOptions{status: http.StatusOK}
+	for _, config := range configs {
+		config(options)
+	}
+
+	return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		if !isJSONContentType(req.Header.Get("Content-Type")) {
+			return DefaultResponse(ctx, req, http.StatusUnsupportedMediaType)
+		}
+
+		payload, err := decodeJSON[Req](req, options.disallowUnknownFields)
+		if err != nil {
+			return GetBadRequestHandler(ctx)(ctx, req, logger, err)
+		}
+
+		result, err := f(ctx, payload, logger)
+		if err != nil {
+			return GetErrorMapper(ctx)(ctx, req, logger, err)
+		}
+
+		body, err := json.Marshal(result)
+		if err != nil {
+			logger.Error("Failed to serialize response (%s)", err.Error())
+			return DefaultResponse(ctx, req, http.StatusInternalServerError)
+		}
+
+		resp := response.Respond(body)
+		resp.SetStatusCode(options.status)
+		resp.SetHeader("Content-Type", "application/json")
+		return resp
+	}
+}
+
+func decodeJSON[T any](req *http.Request, disallowUnknownFields bool) (T, error) {
+	var payload T
+	if req.Body == nil {
+		return payload, nil
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	if disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(&payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			return payload, nil
+		}
+
+		return payload, fmt.Errorf("malformed request body (%s)", err.Error())
+	}
+
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return payload, fmt.Errorf("malformed request body (unexpected data after JSON value)")
+	}
+
+	return payload, nil
+}
+
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
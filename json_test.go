@@ -0,0 +1,110 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aphistic/sweet"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type JSONSuite struct{}
+
+type (
+	greetRequest struct {
+		Name string `json:"name"`
+	}
+
+	greetResponse struct {
+		Greeting string `json:"greeting"`
+	}
+)
+
+func (s *JSONSuite) TestDecodeAndEncode(t sweet.T) {
+	handler := JSON(greet)
+
+	resp := serveJSON(handler, "POST", `{"name": "alice"}`, "application/json")
+	Expect(resp.Code).To(Equal(http.StatusOK))
+	Expect(resp.Body.String()).To(MatchJSON(`{"greeting": "hello alice"}`))
+}
+
+func (s *JSONSuite) TestStatus(t sweet.T) {
+	handler := JSON(greet, WithJSONStatus(http.StatusCreated))
+
+	resp := serveJSON(handler, "POST", `{"name": "alice"}`, "application/json; charset=utf-8")
+	Expect(resp.Code).To(Equal(http.StatusCreated))
+	Expect(resp.Body.String()).To(MatchJSON(`{"greeting": "hello alice"}`))
+}
+
+func (s *JSONSuite) TestEmptyBody(t sweet.T) {
+	handler := JSON(greet)
+
+	resp := serveJSON(handler, "GET", "", "")
+	Expect(resp.Code).To(Equal(http.StatusOK))
+	Expect(resp.Body.String()).To(MatchJSON(`{"greeting": "hello "}`))
+}
+
+func (s *JSONSuite) TestMalformedBody(t sweet.T) {
+	handler := JSON(greet)
+
+	Expect(serveJSON(handler, "POST", `{"name": `, "application/json").Code).To(Equal(http.StatusBadRequest))
+	Expect(serveJSON(handler, "POST", `{"name": 3}`, "application/json").Code).To(Equal(http.StatusBadRequest))
+	Expect(serveJSON(handler, "POST", `{"name": "a"} {}`, "application/json").Code).To(Equal(http.StatusBadRequest))
+	Expect(serveJSON(handler, "POST", `{"name": "a"}]`, "application/json").Code).To(Equal(http.StatusBadRequest))
+	Expect(serveJSON(handler, "POST", `{"name": "a"}}`, "application/json").Code).To(Equal(http.StatusBadRequest))
+	Expect(serveJSON(handler, "POST", "{\"name\": \"a\"}\n", "application/json").Code).To(Equal(http.StatusOK))
+}
+
+func (s *JSONSuite) TestUnknownFields(t sweet.T) {
+	body := `{"name": "alice", "age": 30}`
+	Expect(serveJSON(JSON(greet), "POST", body, "application/json").Code).To(Equal(http.StatusOK))
+	Expect(serveJSON(JSON(greet, WithDisallowUnknownFields()), "POST", body, "application/json").Code).To(Equal(http.StatusBadRequest))
+}
+
+func (s *JSONSuite) TestUnsupportedMediaType(t sweet.T) {
+	handler := JSON(greet)
+
+	Expect(serveJSON(handler, "POST", `{"name": "alice"}`, "text/plain").Code).To(Equal(http.StatusUnsupportedMediaType))
+	Expect(serveJSON(handler, "POST", `{"name": "alice"}`, "application/vnd.api+json").Code).To(Equal(http.StatusOK))
+}
+
+func (s *JSONSuite) TestUnserializableResponse(t sweet.T) {
+	handler := JSON(func(ctx context.Context, req greetRequest, logger nacelle.Logger) (map[string]interface{}, error) {
+		return map[string]interface{}{"greeting": func() {}}, nil
+	})
+
+	resp := serveJSON(handler, "POST", `{"name": "alice"}`, "application/json")
+	Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+	Expect(resp.Body.String()).To(BeEmpty())
+}
+
+func (s *JSONSuite) TestError(t sweet.T) {
+	handler := JSON(func(ctx context.Context, req greetRequest, logger nacelle.Logger) (*greetResponse, error) {
+		return nil, NewNotFoundError("no user named %s", req.Name)
+	})
+
+	resp := serveJSON(handler, "POST", `{"name": "bob"}`, "application/json")
+	Expect(resp.Code).To(Equal(http.StatusNotFound))
+	Expect(resp.Body.String()).To(MatchJSON(`{"status": 404, "message": "no user named bob"}`))
+}
+
+//
+//
+
+func greet(ctx context.Context, req greetRequest, logger nacelle.Logger) (greetResponse, error) {
+	return greetResponse{Greeting: "hello " + req.Name}, nil
+}
+
+func serveJSON(handler Handler, method, body, contentType string) *httptest.ResponseRecorder {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(router.Register("/greet", &EmptySpec{}, WithMethodHandler(method, handler))).To(BeNil())
+
+	headers := map[string]string{}
+	if contentType != "" {
+		headers["Content-Type"] = contentType
+	}
+
+	return serveRequestBody(router, method, "/greet", body, headers)
+}
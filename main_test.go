@@ -3,6 +3,8 @@ package chevron
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/aphistic/sweet"
@@ -22,6 +24,23 @@ func TestMain(m *testing.M) {
 		s.AddSuite(&MiddlewareOptionsSuite{})
 		s.AddSuite(&SpecSuite{})
 		s.AddSuite(&ResourceSuite{})
+		s.AddSuite(&PathParamsSuite{})
+		s.AddSuite(&MethodsSuite{})
+		s.AddSuite(&RouteTableSuite{})
+		s.AddSuite(&OverlapSuite{})
+		s.AddSuite(&ErrorMapperSuite{})
+		s.AddSuite(&JSONSuite{})
+		s.AddSuite(&BindSuite{})
+		s.AddSuite(&NegotiateSuite{})
+		s.AddSuite(&ProblemSuite{})
+		s.AddSuite(&PathPolicySuite{})
+		s.AddSuite(&StaticSuite{})
+		s.AddSuite(&VersioningSuite{})
+		s.AddSuite(&ServerConfigSuite{})
+		s.AddSuite(&RouteFileSuite{})
+		s.AddSuite(&BootSuite{})
+		s.AddSuite(&HealthSuite{})
+		s.AddSuite(&DrainSuite{})
 	})
 }
 
@@ -33,3 +52,18 @@ func makeEmptyHandler(status int) Handler {
 		return response.Empty(status)
 	}
 }
+
+func serveRequest(handler http.Handler, method, url string, headers map[string]string) *httptest.ResponseRecorder {
+	return serveRequestBody(handler, method, url, "", headers)
+}
+
+func serveRequestBody(handler http.Handler, method, url, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
@@ -1,5 +1,10 @@
 package chevron
 
+import (
+	"sort"
+	"sync"
+)
+
 // Method is an enumeration of HTTP methods.
 type Method int
 
@@ -21,10 +26,14 @@ const (
 
 	// MethodDelete represents the DELETE HTTP method.
 	MethodDelete
+
+	// MethodHead represents the HEAD HTTP method.
+	MethodHead
 )
 
 var allMethods = []Method{
 	MethodGet,
+	MethodHead,
 	MethodOptions,
 	MethodPost,
 	MethodPut,
@@ -34,6 +43,7 @@ var allMethods = []Method{
 
 var methodMap = map[string]Method{
 	"GET":     MethodGet,
+	"HEAD":    MethodHead,
 	"OPTIONS": MethodOptions,
 	"POST":    MethodPost,
 	"PUT":     MethodPut,
@@ -43,6 +53,7 @@ var methodMap = map[string]Method{
 
 var methodStrings = map[Method]string{
 	MethodGet:     "GET",
+	MethodHead:    "HEAD",
 	MethodOptions: "OPTIONS",
 	MethodPost:    "POST",
 	MethodPut:     "PUT",
@@ -50,7 +61,101 @@ var methodStrings = map[Method]string{
 	MethodDelete:  "DELETE",
 }
 
+var methodMutex sync.RWMutex
+
+// RegisterMethod returns the Method representing the given HTTP method name.
+// Names which do not correspond to one of the built-in methods (e.g. PROPFIND
+// or QUERY) are assigned a new value on first use. The returned value can be
+// supplied to WithMiddlewareFor like the built-in methods.
+func RegisterMethod(name string) Method {
+	methodMutex.Lock()
+	defer methodMutex.Unlock()
+
+	if method, ok := methodMap[name]; ok {
+		return method
+	}
+
+	method := Method(len(methodMap))
+	methodMap[name] = method
+	methodStrings[method] = name
+	return method
+}
+
 // String returns the uppercased HTTP method name.
 func (m Method) String() string {
+	methodMutex.RLock()
+	defer methodMutex.RUnlock()
+
 	return methodStrings[m]
 }
+
+// lookupMethod returns the Method representing the given HTTP method name,
+// if one has been registered.
+func lookupMethod(name string) (Method, bool) {
+	methodMutex.RLock()
+	defer methodMutex.RUnlock()
+
+	method, ok := methodMap[name]
+	return method, ok
+}
+
+// registeredMethods returns the built-in methods and all extension methods
+// registered via RegisterMethod.
+func registeredMethods() []Method {
+	methodMutex.RLock()
+	methods := make([]Method, 0, len(methodMap))
+	for _, method := range methodMap {
+		methods = append(methods, method)
+	}
+	methodMutex.RUnlock()
+
+	sortMethods(methods)
+	return methods
+}
+
+// containsMethod determines if the given method is in the given list.
+func containsMethod(methods []Method, method Method) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// insertMethod adds the given method to the given list, unless it is already
+// present. Built-in methods are kept in the order in which they are declared
+// in allMethods, followed by extension methods ordered by name.
+func insertMethod(methods []Method, method Method) []Method {
+	if containsMethod(methods, method) {
+		return methods
+	}
+
+	inserted := append(append([]Method{}, methods...), method)
+	sortMethods(inserted)
+	return inserted
+}
+
+// sortMethods sorts the given methods in place. Built-in methods are ordered
+// as they are declared in allMethods, followed by extension methods ordered
+// by name.
+func sortMethods(methods []Method) {
+	index := func(method Method) int {
+		for i, m := range allMethods {
+			if m == method {
+				return i
+			}
+		}
+
+		return len(allMethods)
+	}
+
+	sort.SliceStable(methods, func(i, j int) bool {
+		if ii, ij := index(methods[i]), index(methods[j]); ii != ij {
+			return ii < ij
+		}
+
+		return methods[i].String() < methods[j].String()
+	})
+}
@@ -0,0 +1,34 @@
+package chevron
+
+import (
+	"github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type MethodsSuite struct{}
+
+func (s *MethodsSuite) TestRegisterMethod(t sweet.T) {
+	Expect(RegisterMethod("GET")).To(Equal(MethodGet))
+	Expect(RegisterMethod("HEAD")).To(Equal(MethodHead))
+
+	method := RegisterMethod("MKCOL")
+	Expect(method.String()).To(Equal("MKCOL"))
+	Expect(RegisterMethod("MKCOL")).To(Equal(method))
+
+	lookup, ok := lookupMethod("MKCOL")
+	Expect(ok).To(BeTrue())
+	Expect(lookup).To(Equal(method))
+}
+
+func (s *MethodsSuite) TestInsertMethod(t sweet.T) {
+	var (
+		copyMethod = RegisterMethod("COPY")
+		lockMethod = RegisterMethod("LOCK")
+		methods    = []Method{MethodPost, lockMethod, MethodGet}
+	)
+
+	sortMethods(methods)
+	Expect(methods).To(Equal([]Method{MethodGet, MethodPost, lockMethod}))
+	Expect(insertMethod(methods, copyMethod)).To(Equal([]Method{MethodGet, MethodPost, copyMethod, lockMethod}))
+	Expect(insertMethod(methods, MethodHead)).To(Equal([]Method{MethodGet, MethodHead, MethodPost, lockMethod}))
+}
@@ -23,6 +23,15 @@ type (
 		Convert(Handler) (Handler, error)
 	}
 
+	// NamedMiddleware is middleware that supplies the name under which it is
+	// reported in the router's route table.
+	NamedMiddleware interface {
+		Middleware
+
+		// Name returns the name of the middleware.
+		Name() string
+	}
+
 	// MiddlewareFunc is signature for single-function middleware.
 	MiddlewareFunc func(Handler) (Handler, error)
 )
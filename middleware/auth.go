@@ -43,10 +43,7 @@ func (f AuthorizerFunc) Authorize(ctx context.Context, req *http.Request) (AuthR
 
 func NewAuthMiddleware(authorizer Authorizer, configs ...AuthMiddlewareConfigFunc) chevron.Middleware {
 	m := &AuthMiddleware{
-		authorizer:                  authorizer,
-		errorFactory:                defaultErrorFactory,
-		forbiddenResponseFactory:    defaultForbiddenResponseFactory,
-		unauthorizedResponseFactory: defaultUnauthorizedResponseFactory,
+		authorizer: authorizer,
 	}
 
 	for _, f := range configs {
@@ -62,15 +59,15 @@ func (m *AuthMiddleware) Convert(f chevron.Handler) (chevron.Handler, error) {
 
 		switch result {
 		case AuthResultForbidden:
-			return m.forbiddenResponseFactory()
+			return m.forbiddenResponseFactory.create(ctx, req, http.StatusForbidden)
 		case AuthResultUnauthorized:
-			return m.unauthorizedResponseFactory(err)
+			return m.unauthorizedResponseFactory.create(ctx, req, http.StatusUnauthorized, err)
 		default:
 		}
 
 		if err != nil {
 			logger.Error("failed to invoke authorizer (%s)", err.Error())
-			return m.errorFactory(err)
+			return m.errorFactory.create(ctx, req, http.StatusInternalServerError, err)
 		}
 
 		return f(context.WithValue(ctx, TokenAuthPayload, payload), req, logger)
@@ -78,11 +75,3 @@ func (m *AuthMiddleware) Convert(f chevron.Handler) (chevron.Handler, error) {
 
 	return handler, nil
 }
-
-func defaultForbiddenResponseFactory() response.Response {
-	return response.Empty(http.StatusForbidden)
-}
-
-func defaultUnauthorizedResponseFactory(err error) response.Response {
-	return response.Empty(http.StatusUnauthorized)
-}
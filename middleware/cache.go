@@ -28,8 +28,7 @@ func NewResponseCache(
 	configs ...CacheMiddlewareConfigFunc,
 ) chevron.Middleware {
 	m := &CacheMiddleware{
-		cache:        cache,
-		errorFactory: defaultErrorFactory,
+		cache: cache,
 	}
 
 	for _, config := range configs {
@@ -51,7 +50,7 @@ func (m *CacheMiddleware) Convert(f chevron.Handler) (chevron.Handler, error) {
 		val, err := m.generateCacheValue(ctx, req, logger, f)
 		if err != nil {
 			logger.Error("failed to retrieve response from cache (%s)", err.Error())
-			return m.errorFactory(err)
+			return m.errorFactory.create(ctx, req, http.StatusInternalServerError, err)
 		}
 
 		// Value is either from the cache or was just generated and
@@ -62,7 +61,7 @@ func (m *CacheMiddleware) Convert(f chevron.Handler) (chevron.Handler, error) {
 		resp, err := deserialize(val)
 		if err != nil {
 			logger.Error("failed to round-trip response (%s)", err.Error())
-			return m.errorFactory(err)
+			return m.errorFactory.create(ctx, req, http.StatusInternalServerError, err)
 		}
 
 		return resp
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+
+	"github.com/go-nacelle/chevron"
+)
+
+type CORSMiddleware struct {
+	origins          map[string]struct{}
+	allowAllOrigins  bool
+	methods          []string
+	headers          []string
+	exposedHeaders   []string
+	allowCredentials bool
+	maxAge           int
+}
+
+// NewCORS creates middleware that implements cross-origin resource sharing
+// for the given origins. The origin `*` allows requests from every origin.
+// Preflight requests from an allowed origin are answered directly with a
+// 204-level response. Requests from other origins are passed to the wrapped
+// handler without CORS headers, which causes browsers to reject them.
+func NewCORS(origins []string, configs ...CORSConfigFunc) chevron.Middleware {
+	m := &CORSMiddleware{
+		origins: map[string]struct{}{},
+		methods: []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+	}
+
+	for _, origin := range origins {
+		if origin == "*" {
+			m.allowAllOrigins = true
+		}
+
+		m.origins[strings.ToLower(origin)] = struct{}{}
+	}
+
+	for _, f := range configs {
+		f(m)
+	}
+
+	return m
+}
+
+func (m *CORSMiddleware) Convert(f chevron.Handler) (chevron.Handler, error) {
+	handler := func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		origin := req.Header.Get("Origin")
+		if origin == "" || !m.allowed(origin) {
+			return f(ctx, req, logger)
+		}
+
+		if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" {
+			return m.decorate(m.preflight(req), origin)
+		}
+
+		resp := f(ctx, req, logger)
+
+		if len(m.exposedHeaders) > 0 {
+			resp.SetHeader("Access-Control-Expose-Headers", strings.Join(m.exposedHeaders, ", "))
+		}
+
+		return m.decorate(resp, origin)
+	}
+
+	return handler, nil
+}
+
+func (m *CORSMiddleware) allowed(origin string) bool {
+	if m.allowAllOrigins {
+		return true
+	}
+
+	_, ok := m.origins[strings.ToLower(origin)]
+	return ok
+}
+
+func (m *CORSMiddleware) preflight(req *http.Request) response.Response {
+	resp := response.Empty(http.StatusNoContent)
+	resp.SetHeader("Access-Control-Allow-Methods", strings.Join(m.methods, ", "))
+
+	headers := strings.Join(m.headers, ", ")
+	if len(m.headers) == 0 {
+		// Allow any headers the client intends to send
+		headers = req.Header.Get("Access-Control-Request-Headers")
+	}
+
+	if headers != "" {
+		resp.SetHeader("Access-Control-Allow-Headers", headers)
+	}
+
+	if m.maxAge > 0 {
+		resp.SetHeader("Access-Control-Max-Age", fmt.Sprintf("%d", m.maxAge))
+	}
+
+	return resp
+}
+
+func (m *CORSMiddleware) decorate(resp response.Response, origin string) response.Response {
+	if m.allowAllOrigins && !m.allowCredentials {
+		resp.SetHeader("Access-Control-Allow-Origin", "*")
+	} else {
+		resp.SetHeader("Access-Control-Allow-Origin", origin)
+		resp.AddHeader("Vary", "Origin")
+	}
+
+	if m.allowCredentials {
+		resp.SetHeader("Access-Control-Allow-Credentials", "true")
+	}
+
+	return resp
+}
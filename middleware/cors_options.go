@@ -0,0 +1,23 @@
+package middleware
+
+type CORSConfigFunc func(*CORSMiddleware)
+
+func WithCORSMethods(methods ...string) CORSConfigFunc {
+	return func(m *CORSMiddleware) { m.methods = methods }
+}
+
+func WithCORSHeaders(headers ...string) CORSConfigFunc {
+	return func(m *CORSMiddleware) { m.headers = headers }
+}
+
+func WithCORSExposedHeaders(headers ...string) CORSConfigFunc {
+	return func(m *CORSMiddleware) { m.exposedHeaders = headers }
+}
+
+func WithCORSAllowCredentials(allowCredentials bool) CORSConfigFunc {
+	return func(m *CORSMiddleware) { m.allowCredentials = allowCredentials }
+}
+
+func WithCORSMaxAge(seconds int) CORSConfigFunc {
+	return func(m *CORSMiddleware) { m.maxAge = seconds }
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type CORSSuite struct{}
+
+func (s *CORSSuite) TestSimpleRequest(t sweet.T) {
+	wrapped, err := NewCORS([]string{"https://example.com"}, WithCORSExposedHeaders("X-Total")).Convert(okCORSHandler)
+	Expect(err).To(BeNil())
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	resp := wrapped(context.Background(), r, nacelle.NewNilLogger())
+
+	Expect(resp.StatusCode()).To(Equal(http.StatusOK))
+	Expect(resp.Header("Access-Control-Allow-Origin")).To(Equal("https://example.com"))
+	Expect(resp.Header("Access-Control-Expose-Headers")).To(Equal("X-Total"))
+	Expect(resp.Header("Vary")).To(Equal("Origin"))
+}
+
+func (s *CORSSuite) TestDisallowedOrigin(t sweet.T) {
+	wrapped, err := NewCORS([]string{"https://example.com"}).Convert(okCORSHandler)
+	Expect(err).To(BeNil())
+
+	r, _ := http.NewRequest("OPTIONS", "/", nil)
+	r.Header.Set("Origin", "https://evil.com")
+	r.Header.Set("Access-Control-Request-Method", "DELETE")
+	resp := wrapped(context.Background(), r, nacelle.NewNilLogger())
+
+	Expect(resp.StatusCode()).To(Equal(http.StatusOK))
+	Expect(resp.Header("Access-Control-Allow-Origin")).To(BeEmpty())
+}
+
+func (s *CORSSuite) TestPreflight(t sweet.T) {
+	wrapped, err := NewCORS([]string{"*"}, WithCORSMaxAge(600)).Convert(okCORSHandler)
+	Expect(err).To(BeNil())
+
+	r, _ := http.NewRequest("OPTIONS", "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	r.Header.Set("Access-Control-Request-Method", "DELETE")
+	r.Header.Set("Access-Control-Request-Headers", "Authorization")
+	resp := wrapped(context.Background(), r, nacelle.NewNilLogger())
+
+	Expect(resp.StatusCode()).To(Equal(http.StatusNoContent))
+	Expect(resp.Header("Access-Control-Allow-Origin")).To(Equal("*"))
+	Expect(resp.Header("Access-Control-Allow-Methods")).To(ContainSubstring("DELETE"))
+	Expect(resp.Header("Access-Control-Allow-Headers")).To(Equal("Authorization"))
+	Expect(resp.Header("Access-Control-Max-Age")).To(Equal("600"))
+}
+
+func (s *CORSSuite) TestCredentials(t sweet.T) {
+	wrapped, err := NewCORS([]string{"*"}, WithCORSAllowCredentials(true)).Convert(okCORSHandler)
+	Expect(err).To(BeNil())
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	resp := wrapped(context.Background(), r, nacelle.NewNilLogger())
+
+	Expect(resp.Header("Access-Control-Allow-Origin")).To(Equal("https://example.com"))
+	Expect(resp.Header("Access-Control-Allow-Credentials")).To(Equal("true"))
+}
+
+//
+//
+
+func okCORSHandler(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusOK)
+}
@@ -22,5 +22,7 @@ func TestMain(m *testing.M) {
 		s.AddSuite(&RecoverSuite{})
 		s.AddSuite(&RequestIDSuite{})
 		s.AddSuite(&SchemaSuite{})
+		s.AddSuite(&CORSSuite{})
+		s.AddSuite(&TimeoutSuite{})
 	})
 }
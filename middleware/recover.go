@@ -23,7 +23,6 @@ type (
 // logged at error level.
 func NewRecovery(configs ...RecoverConfigFunc) chevron.Middleware {
 	m := &RecoverMiddleware{
-		errorFactory:     defaultPanicErrorFactory,
 		stackBufferSize:  4 << 10,
 		logAllGoroutines: false,
 	}
@@ -50,7 +49,7 @@ func (m *RecoverMiddleware) Convert(f chevron.Handler) (chevron.Handler, error)
 					stack[:length],
 				)
 
-				resp = m.errorFactory(err)
+				resp = m.errorFactory.create(ctx, req, err)
 			}
 		}()
 
@@ -37,13 +37,13 @@ func GetRequestID(ctx context.Context) string {
 
 // NewRequestID creates middleware that generates a unique ID for the request.
 // If the header X-Request-ID is present in the request, that value is used
-// instead. The request ID is added to the context and to logger attributes,
+// instead. The request ID is added to the context, to logger attributes, and
+// to the X-Request-ID header of the request passed to the wrapped handler,
 // and the X-Request-ID header is added to the wrapped handler's resulting
 // response.
 func NewRequestID(configs ...RequestIDConfigFunc) *RequestIDMiddleware {
 	m := &RequestIDMiddleware{
 		requestIDGenerator: defaultRequestIDGenerator,
-		errorFactory:       defaultErrorFactory,
 	}
 
 	for _, f := range configs {
@@ -58,7 +58,13 @@ func (m *RequestIDMiddleware) Convert(f chevron.Handler) (chevron.Handler, error
 		requestID, err := m.getIDFromRequest(req)
 		if err != nil {
 			logger.Error("Failed to generate request ID (%s)", err.Error())
-			return m.errorFactory(err)
+			return m.errorFactory.create(ctx, req, http.StatusInternalServerError, err)
+		}
+
+		if req.Header.Get("X-Request-ID") == "" {
+			// Expose a generated ID to the wrapped handler
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Request-ID", requestID)
 		}
 
 		wrappedCtx := context.WithValue(
@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/efritz/response"
+	"github.com/go-nacelle/chevron"
 )
 
 type (
@@ -12,10 +14,34 @@ type (
 	PanicErrorFactory func(interface{}) response.Response
 )
 
-func defaultErrorFactory(val error) response.Response {
-	return response.Empty(http.StatusInternalServerError)
+// create invokes the factory. If no factory was configured, the router's
+// default response for the given status is created instead, which is a
+// problem details response if the router was configured to use them.
+func (f ResponseFactory) create(ctx context.Context, req *http.Request, status int) response.Response {
+	if f == nil {
+		return chevron.DefaultResponse(ctx, req, status)
+	}
+
+	return f()
 }
 
-func defaultPanicErrorFactory(val interface{}) response.Response {
-	return response.Empty(http.StatusInternalServerError)
+// create invokes the factory with the given error. If no factory was
+// configured, the router's default response for the given status is created
+// instead.
+func (f ErrorFactory) create(ctx context.Context, req *http.Request, status int, err error) response.Response {
+	if f == nil {
+		return chevron.DefaultResponse(ctx, req, status)
+	}
+
+	return f(err)
+}
+
+// create invokes the factory with the given panic value. If no factory was
+// configured, the router's default 500-level response is created instead.
+func (f PanicErrorFactory) create(ctx context.Context, req *http.Request, val interface{}) response.Response {
+	if f == nil {
+		return chevron.DefaultResponse(ctx, req, http.StatusInternalServerError)
+	}
+
+	return f(val)
 }
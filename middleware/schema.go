@@ -43,10 +43,7 @@ func GetJSONData(ctx context.Context) []byte {
 
 func NewSchemaMiddleware(path string, configs ...SchemaConfigFunc) chevron.Middleware {
 	m := &SchemaMiddleware{
-		path:                       path,
-		errorFactory:               defaultErrorFactory,
-		badRequestFactory:          defaultBadRequestFactory,
-		unprocessableEntityFactory: defaultUnprocessableEntityFactory,
+		path: path,
 	}
 
 	for _, f := range configs {
@@ -68,21 +65,21 @@ func (m *SchemaMiddleware) Convert(f chevron.Handler) (chevron.Handler, error) {
 		data, err := ioutil.ReadAll(req.Body)
 		if err != nil {
 			logger.Error("Failed to read request body (%s)", err.Error())
-			return m.errorFactory(err)
+			return m.errorFactory.create(ctx, req, http.StatusInternalServerError, err)
 		}
 
 		if !isJSON(data) {
-			return m.badRequestFactory()
+			return m.badRequestFactory.create(ctx, req)
 		}
 
 		result, err := schema.Validate(gojsonschema.NewStringLoader(string(data)))
 		if err != nil {
 			logger.Error("Failed to load json schema", err.Error())
-			return m.errorFactory(err)
+			return m.errorFactory.create(ctx, req, http.StatusInternalServerError, err)
 		}
 
 		if !result.Valid() {
-			return m.unprocessableEntityFactory(result.Errors())
+			return m.unprocessableEntityFactory.create(ctx, req, result.Errors())
 		}
 
 		return f(context.WithValue(ctx, TokenJSONData, data), req, logger)
@@ -144,10 +141,22 @@ func loadYAMLSchema(path string) (gojsonschema.JSONLoader, error) {
 	return gojsonschema.NewBytesLoader(json), nil
 }
 
-func defaultBadRequestFactory() response.Response {
-	return response.Empty(http.StatusBadRequest)
+// create invokes the factory. If no factory was configured, the router's
+// default 400-level response is created instead.
+func (f SchemaBadRequestFactory) create(ctx context.Context, req *http.Request) response.Response {
+	if f == nil {
+		return chevron.DefaultResponse(ctx, req, http.StatusBadRequest)
+	}
+
+	return f()
 }
 
-func defaultUnprocessableEntityFactory([]gojsonschema.ResultError) response.Response {
-	return response.Empty(http.StatusUnprocessableEntity)
+// create invokes the factory with the given validation errors. If no factory
+// was configured, the router's default 422-level response is created instead.
+func (f SchemaUnprocessableEntityFactory) create(ctx context.Context, req *http.Request, resultErrors []gojsonschema.ResultError) response.Response {
+	if f == nil {
+		return chevron.DefaultResponse(ctx, req, http.StatusUnprocessableEntity)
+	}
+
+	return f(resultErrors)
 }
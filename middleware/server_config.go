@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"github.com/go-nacelle/httpbase"
+
+	"github.com/go-nacelle/chevron"
+)
+
+// NewInitializer creates a chevron.ServerInitializer which applies the built-in
+// middleware enabled by the server config. A middleware factory supplied via
+// the given configs takes precedence.
+func NewInitializer(initializer chevron.RouteInitializer, configs ...chevron.InitializerConfig) httpbase.ServerInitializer {
+	configs = append([]chevron.InitializerConfig{chevron.WithServerMiddleware(FromServerConfig)}, configs...)
+	return chevron.NewInitializer(initializer, configs...)
+}
+
+// BootAndExit behaves like chevron.BootAndExit, except that each registered
+// server applies the built-in middleware enabled by its server config. This
+// method does not return.
+func BootAndExit(name string, initializer chevron.RouteInitializer, configs ...chevron.BootConfig) {
+	configs = append([]chevron.BootConfig{chevron.WithDefaultServerMiddleware(FromServerConfig)}, configs...)
+	chevron.BootAndExit(name, initializer, configs...)
+}
+
+// FromServerConfig creates the built-in middleware enabled by the given server
+// config. It conforms to chevron.ServerMiddlewareFactory and is registered to
+// a server initializer via chevron.WithServerMiddleware. The middleware is
+// ordered from outermost to innermost as request ID, logging, recovery, CORS,
+// timeout, and gzip.
+func FromServerConfig(config *chevron.ServerConfig) ([]chevron.Middleware, error) {
+	middleware := []chevron.Middleware{}
+
+	if config.RequestID {
+		middleware = append(middleware, NewRequestID())
+	}
+
+	if config.Logging {
+		middleware = append(middleware, NewLogging())
+	}
+
+	if config.Recovery {
+		middleware = append(middleware, NewRecovery())
+	}
+
+	if len(config.CORSOrigins) > 0 {
+		middleware = append(middleware, NewCORS(config.CORSOrigins))
+	}
+
+	if config.RequestTimeout > 0 {
+		middleware = append(middleware, NewTimeout(config.RequestTimeout))
+	}
+
+	if config.Gzip {
+		middleware = append(middleware, NewGzip(WithGzipLevel(config.GzipLevel)))
+	}
+
+	return middleware, nil
+}
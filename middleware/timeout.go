@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+
+	"github.com/go-nacelle/chevron"
+)
+
+type (
+	TimeoutMiddleware struct {
+		timeout         time.Duration
+		responseFactory ResponseFactory
+	}
+
+	// timeoutResult is the response of a handler invoked by the timeout
+	// middleware, or the value with which the handler panicked.
+	timeoutResult struct {
+		resp     response.Response
+		panicked bool
+		value    interface{}
+		stack    []byte
+	}
+)
+
+// NewTimeout creates middleware that cancels the context of the wrapped
+// handler after the given duration. If the handler has not returned by
+// then, a 503-level response is returned in its place and the handler's
+// eventual response is discarded. The handler is invoked on a separate
+// goroutine. A panic raised by the handler before the timeout is logged at
+// error level along with the stack of that goroutine, then raised again on
+// the calling goroutine so that it can be captured by recovery middleware.
+// A panic raised after the timeout is only logged.
+func NewTimeout(timeout time.Duration, configs ...TimeoutConfigFunc) chevron.Middleware {
+	m := &TimeoutMiddleware{
+		timeout: timeout,
+	}
+
+	for _, f := range configs {
+		f(m)
+	}
+
+	return m
+}
+
+func (m *TimeoutMiddleware) Convert(f chevron.Handler) (chevron.Handler, error) {
+	handler := func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		ctx, cancel := context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+
+		var (
+			ch        = make(chan timeoutResult, 1)
+			mutex     sync.Mutex
+			abandoned bool
+		)
+
+		go func() {
+			defer func() {
+				if err := recover(); err != nil {
+					result := timeoutResult{panicked: true, value: err, stack: debug.Stack()}
+
+					mutex.Lock()
+					defer mutex.Unlock()
+
+					if abandoned {
+						logAbandonedPanic(logger, result)
+						return
+					}
+
+					ch <- result
+				}
+			}()
+
+			ch <- timeoutResult{resp: f(ctx, req, logger)}
+		}()
+
+		select {
+		case result := <-ch:
+			if result.panicked {
+				// The stack of the handler's goroutine is lost once the
+				// panic is raised again, so it is logged here
+				logger.Error("Request handler panicked (%s):\n%s", result.value, result.stack)
+				panic(result.value)
+			}
+
+			return result.resp
+
+		case <-ctx.Done():
+			mutex.Lock()
+			abandoned = true
+			mutex.Unlock()
+
+			select {
+			case result := <-ch:
+				if result.panicked {
+					logAbandonedPanic(logger, result)
+				}
+			default:
+			}
+
+			logger.Warning("Request handler did not complete within %s", m.timeout)
+			return m.responseFactory.create(ctx, req, http.StatusServiceUnavailable)
+		}
+	}
+
+	return handler, nil
+}
+
+// logAbandonedPanic logs a panic raised by a handler which did not complete
+// before its timeout.
+func logAbandonedPanic(logger nacelle.Logger, result timeoutResult) {
+	logger.Error("Request handler panicked after timing out (%s):\n%s", result.value, result.stack)
+}
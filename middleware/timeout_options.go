@@ -0,0 +1,7 @@
+package middleware
+
+type TimeoutConfigFunc func(*TimeoutMiddleware)
+
+func WithTimeoutResponseFactory(factory ResponseFactory) TimeoutConfigFunc {
+	return func(m *TimeoutMiddleware) { m.responseFactory = factory }
+}
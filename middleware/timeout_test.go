@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-nacelle/chevron"
+)
+
+type TimeoutSuite struct{}
+
+func (s *TimeoutSuite) TestCompleted(t sweet.T) {
+	bare := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+		return response.Empty(http.StatusNoContent)
+	}
+
+	wrapped, err := NewTimeout(time.Second).Convert(bare)
+	Expect(err).To(BeNil())
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	resp := wrapped(context.Background(), r, nacelle.NewNilLogger())
+	Expect(resp.StatusCode()).To(Equal(http.StatusNoContent))
+}
+
+func (s *TimeoutSuite) TestTimeout(t sweet.T) {
+	canceled := make(chan struct{})
+	bare := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+		<-ctx.Done()
+		close(canceled)
+		return response.Empty(http.StatusNoContent)
+	}
+
+	wrapped, err := NewTimeout(time.Millisecond * 10).Convert(bare)
+	Expect(err).To(BeNil())
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	resp := wrapped(context.Background(), r, nacelle.NewNilLogger())
+	Expect(resp.StatusCode()).To(Equal(http.StatusServiceUnavailable))
+	Eventually(canceled).Should(BeClosed())
+}
+
+func (s *TimeoutSuite) TestTimeoutCustomResponse(t sweet.T) {
+	bare := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+		<-ctx.Done()
+		return response.Empty(http.StatusNoContent)
+	}
+
+	factory := func() response.Response {
+		return response.Empty(http.StatusGatewayTimeout)
+	}
+
+	wrapped, err := NewTimeout(time.Millisecond*10, WithTimeoutResponseFactory(factory)).Convert(bare)
+	Expect(err).To(BeNil())
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	resp := wrapped(context.Background(), r, nacelle.NewNilLogger())
+	Expect(resp.StatusCode()).To(Equal(http.StatusGatewayTimeout))
+}
+
+func (s *TimeoutSuite) TestTimeoutProblemDetails(t sweet.T) {
+	handler := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+		<-ctx.Done()
+		return response.Empty(http.StatusNoContent)
+	}
+
+	router := chevron.NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), chevron.WithProblemDetails())
+	Expect(router.Register("/", &panicSpec{handler: handler}, chevron.WithMiddleware(NewTimeout(time.Millisecond*10)))).To(BeNil())
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, r)
+	Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal(chevron.ProblemContentType))
+	Expect(recorder.Body.String()).To(ContainSubstring(`"status":503`))
+}
+
+func (s *TimeoutSuite) TestPanic(t sweet.T) {
+	bare := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+		panic("oops")
+	}
+
+	wrapped, err := NewTimeout(time.Second).Convert(bare)
+	Expect(err).To(BeNil())
+
+	var (
+		logger = &errorLogger{Logger: nacelle.NewNilLogger(), errors: make(chan string, 1)}
+		r, _   = http.NewRequest("GET", "/", nil)
+		value  interface{}
+	)
+
+	func() {
+		defer func() { value = recover() }()
+		wrapped(context.Background(), r, logger)
+	}()
+
+	// The panic is raised again on the calling goroutine after the stack
+	// of the handler's goroutine is logged
+	Expect(value).To(Equal("oops"))
+	Eventually(logger.errors).Should(Receive(And(
+		HavePrefix("Request handler panicked (oops)"),
+		ContainSubstring("timeout_test.go"),
+	)))
+}
+
+func (s *TimeoutSuite) TestPanicAfterTimeout(t sweet.T) {
+	bare := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+		<-ctx.Done()
+		panic("oops")
+	}
+
+	wrapped, err := NewTimeout(time.Millisecond * 10).Convert(bare)
+	Expect(err).To(BeNil())
+
+	var (
+		logger = &errorLogger{Logger: nacelle.NewNilLogger(), errors: make(chan string, 1)}
+		r, _   = http.NewRequest("GET", "/", nil)
+	)
+
+	resp := wrapped(context.Background(), r, logger)
+	Expect(resp.StatusCode()).To(Equal(http.StatusServiceUnavailable))
+	Eventually(logger.errors).Should(Receive(HavePrefix("Request handler panicked after timing out (oops)")))
+}
+
+func (s *TimeoutSuite) TestRecoveryFromServerConfig(t sweet.T) {
+	middleware, err := FromServerConfig(&chevron.ServerConfig{
+		Recovery:       true,
+		RequestTimeout: time.Second,
+	})
+
+	Expect(err).To(BeNil())
+
+	router := chevron.NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	for _, m := range middleware {
+		Expect(router.Use(m)).To(BeNil())
+	}
+
+	handler := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+		panic("oops")
+	}
+
+	Expect(router.Register("/", &panicSpec{handler: handler})).To(BeNil())
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, r)
+	Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+}
+
+//
+//
+
+type errorLogger struct {
+	nacelle.Logger
+	errors chan string
+}
+
+type panicSpec struct {
+	*chevron.EmptySpec
+	handler chevron.Handler
+}
+
+func (l *errorLogger) Error(format string, args ...interface{}) {
+	l.errors <- fmt.Sprintf(format, args...)
+}
+
+func (s *panicSpec) Get(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+	return s.handler(ctx, r, logger)
+}
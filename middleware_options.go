@@ -3,34 +3,65 @@ package chevron
 type (
 	// MiddlewareConfigFunc is a function that decorates a map from HTTP methods
 	// to handlers.
-	MiddlewareConfigFunc func(handlerMap) error
+	MiddlewareConfigFunc func(*decoration) error
+
+	// decoration is the state of a resource whose handlers are being decorated
+	// at registration. It holds the handler map along with the names of the
+	// middleware applied to each method's handler so far. Configs must replace
+	// handlers via wrap so that the names are recorded.
+	decoration struct {
+		hm    handlerMap
+		trace middlewareTrace
+	}
 )
 
 // WithMiddleware applies the given middleware to all HTTP methods in the
 // handler map.
 func WithMiddleware(middleware Middleware) MiddlewareConfigFunc {
-	return func(hm handlerMap) error {
-		return applyMiddleware(middleware, hm, allMethods)
+	return func(d *decoration) error {
+		return applyMiddleware(middleware, d, d.hm.methods())
 	}
 }
 
 // WithMiddlewareFor applies the given middleware to the provided HTTP
-// methods in the handler map.
+// methods in the handler map. Unless the resource supplies its own HEAD
+// handler, middleware is not applied to the HEAD method directly, as its
+// handler is derived from the decorated GET handler.
 func WithMiddlewareFor(middleware Middleware, methods ...Method) MiddlewareConfigFunc {
-	return func(hm handlerMap) error {
-		return applyMiddleware(middleware, hm, methods)
+	return func(d *decoration) error {
+		return applyMiddleware(middleware, d, methods)
 	}
 }
 
-func applyMiddleware(middleware Middleware, hm handlerMap, methods []Method) error {
+func applyMiddleware(middleware Middleware, d *decoration, methods []Method) error {
 	for _, method := range methods {
-		wrapped, err := middleware.Convert(hm[method])
+		handler, ok := d.hm[method]
+		if !ok {
+			continue
+		}
+
+		wrapped, err := middleware.Convert(handler)
 		if err != nil {
 			return err
 		}
 
-		hm[method] = wrapped
+		d.wrap(method, middlewareName(middleware), wrapped)
 	}
 
 	return nil
 }
+
+func newDecoration(hm handlerMap) *decoration {
+	return &decoration{
+		hm:    hm,
+		trace: middlewareTrace{},
+	}
+}
+
+// wrap replaces the handler of the given method with the given handler and
+// records the name of the middleware that created it. Middleware is applied
+// innermost first, so the name is prepended to the existing names.
+func (d *decoration) wrap(method Method, name string, handler Handler) {
+	d.hm[method] = handler
+	d.trace[method] = append([]string{name}, d.trace[method]...)
+}
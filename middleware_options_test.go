@@ -21,7 +21,7 @@ func (s *MiddlewareOptionsSuite) TestWithMiddleware(t sweet.T) {
 	})
 
 	// Apply the middleware config
-	Expect(WithMiddleware(middleware)(hm)).To(BeNil())
+	Expect(WithMiddleware(middleware)(newDecoration(hm))).To(BeNil())
 
 	Expect(numCalls).To(Equal(6))
 	Expect(hm[MethodGet](nil, nil, nil).StatusCode()).To(Equal(106))
@@ -38,7 +38,7 @@ func (s *MiddlewareOptionsSuite) TestWithMiddlewareError(t sweet.T) {
 	})
 
 	// Apply the middleware config
-	Expect(WithMiddleware(middleware)(makeTestHandlerMap())).To(MatchError("utoh"))
+	Expect(WithMiddleware(middleware)(newDecoration(makeTestHandlerMap()))).To(MatchError("utoh"))
 }
 
 func (s *MiddlewareOptionsSuite) TestWithMiddlewareFor(t sweet.T) {
@@ -53,7 +53,7 @@ func (s *MiddlewareOptionsSuite) TestWithMiddlewareFor(t sweet.T) {
 	})
 
 	// Apply the middleware config
-	Expect(WithMiddlewareFor(middleware, MethodGet, MethodPatch)(hm)).To(BeNil())
+	Expect(WithMiddlewareFor(middleware, MethodGet, MethodPatch)(newDecoration(hm))).To(BeNil())
 
 	Expect(numCalls).To(Equal(2))
 	Expect(hm[MethodGet](nil, nil, nil).StatusCode()).To(Equal(106))
@@ -0,0 +1,221 @@
+package chevron
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/efritz/response"
+	"github.com/ghodss/yaml"
+	"github.com/vmihailenco/msgpack"
+)
+
+type (
+	// Codec serializes response values into a particular media type.
+	Codec struct {
+		// ContentType is the value of the Content-Type header of the
+		// response. Its media type is matched against the Accept header.
+		ContentType string
+
+		// Aliases are additional media types matched against the Accept
+		// header, such as unofficial or legacy names for the media type.
+		Aliases []string
+
+		// Marshal serializes a value into a response body.
+		Marshal func(interface{}) ([]byte, error)
+	}
+
+	mediaRange struct {
+		mediaType string
+		subtype   string
+		quality   float64
+	}
+)
+
+var (
+	// JSONCodec serializes values as JSON.
+	JSONCodec = Codec{
+		ContentType: "application/json",
+		Marshal:     json.Marshal,
+	}
+
+	// YAMLCodec serializes values as YAML.
+	YAMLCodec = Codec{
+		ContentType: "application/yaml",
+		Aliases:     []string{"application/x-yaml", "text/yaml"},
+		Marshal:     yaml.Marshal,
+	}
+
+	// XMLCodec serializes values as XML.
+	XMLCodec = Codec{
+		ContentType: "application/xml",
+		Aliases:     []string{"text/xml"},
+		Marshal:     xml.Marshal,
+	}
+
+	// TextCodec serializes values with their default format.
+	TextCodec = Codec{
+		ContentType: "text/plain; charset=utf-8",
+		Marshal:     marshalText,
+	}
+
+	// MessagePackCodec serializes values as MessagePack.
+	MessagePackCodec = Codec{
+		ContentType: "application/msgpack",
+		Aliases:     []string{"application/x-msgpack"},
+		Marshal:     msgpack.Marshal,
+	}
+
+	// DefaultCodecs are the codecs used by Negotiate when none are supplied,
+	// in order of preference.
+	DefaultCodecs = []Codec{
+		JSONCodec,
+		YAMLCodec,
+		XMLCodec,
+		TextCodec,
+		MessagePackCodec,
+	}
+)
+
+// Negotiate creates a response with the given status code whose body is the
+// given value serialized by the codec which best matches the request's Accept
+// header. Media ranges are weighted by their q-values, and ties are broken by
+// the order of the given codecs (or DefaultCodecs if none are supplied). The
+// first codec is used when the request has no Accept header, and a bare `*`
+// media range is treated as `*/*`. A 406-level response is returned when no
+// codec is acceptable, and a 500-level response is returned when the value
+// cannot be serialized by the selected codec. These are problem details
+// responses if the router serving the request was configured to use them.
+func Negotiate(req *http.Request, status int, value interface{}, codecs ...Codec) response.Response {
+	if len(codecs) == 0 {
+		codecs = DefaultCodecs
+	}
+
+	codec, ok := selectCodec(req.Header.Get("Accept"), codecs)
+	if !ok {
+		return DefaultResponse(req.Context(), req, http.StatusNotAcceptable).SetHeader("Vary", "Accept")
+	}
+
+	body, err := codec.Marshal(value)
+	if err != nil {
+		return DefaultResponse(req.Context(), req, http.StatusInternalServerError).SetHeader("Vary", "Accept")
+	}
+
+	return response.Respond(body).
+		SetStatusCode(status).
+		SetHeader("Content-Type", codec.ContentType).
+		SetHeader("Content-Length", fmt.Sprintf("%d", len(body))).
+		SetHeader("Vary", "Accept")
+}
+
+func selectCodec(accept string, codecs []Codec) (Codec, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return codecs[0], true
+	}
+
+	ranges := parseAccept(accept)
+
+	var (
+		best        Codec
+		bestQuality float64
+	)
+
+	for _, codec := range codecs {
+		if quality := codec.quality(ranges); quality > bestQuality {
+			best, bestQuality = codec, quality
+		}
+	}
+
+	return best, bestQuality > 0
+}
+
+// quality returns the q-value of the most specific media range matching one
+// of the codec's media types.
+func (c Codec) quality(ranges []mediaRange) float64 {
+	quality, specificity := 0.0, -1
+
+	for _, mediaType := range append([]string{c.ContentType}, c.Aliases...) {
+		mediaType, _, err := mime.ParseMediaType(mediaType)
+		if err != nil {
+			continue
+		}
+
+		parts := strings.SplitN(mediaType, "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		for _, r := range ranges {
+			if s := r.match(parts[0], parts[1]); s > specificity {
+				quality, specificity = r.quality, s
+			}
+		}
+	}
+
+	return quality
+}
+
+// match returns the specificity of the media range with respect to the given
+// media type, or -1 if the range does not match the media type.
+func (r mediaRange) match(mediaType, subtype string) int {
+	switch {
+	case r.mediaType == mediaType && r.subtype == subtype:
+		return 2
+	case r.mediaType == mediaType && r.subtype == "*":
+		return 1
+	case r.mediaType == "*" && r.subtype == "*":
+		return 0
+	}
+
+	return -1
+}
+
+func parseAccept(accept string) []mediaRange {
+	ranges := []mediaRange{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		// Some clients send a bare wildcard in place of */*
+		if mediaType == "*" {
+			mediaType = "*/*"
+		}
+
+		types := strings.SplitN(mediaType, "/", 2)
+		if len(types) != 2 {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		ranges = append(ranges, mediaRange{
+			mediaType: types[0],
+			subtype:   types[1],
+			quality:   quality,
+		})
+	}
+
+	return ranges
+}
+
+func marshalText(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+
+	return []byte(fmt.Sprint(value)), nil
+}
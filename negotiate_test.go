@@ -0,0 +1,144 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+	"github.com/vmihailenco/msgpack"
+)
+
+type NegotiateSuite struct{}
+
+type negotiatePayload struct {
+	Name  string `json:"name" xml:"name" msgpack:"name"`
+	Count int    `json:"count" xml:"count" msgpack:"count"`
+}
+
+func (s *NegotiateSuite) TestNoAccept(t sweet.T) {
+	resp := negotiate("", negotiatePayload{"foo", 3})
+	Expect(resp.StatusCode()).To(Equal(http.StatusCreated))
+
+	headers, body, err := response.Serialize(resp)
+	Expect(err).To(BeNil())
+	Expect(headers.Get("Content-Type")).To(Equal("application/json"))
+	Expect(headers.Get("Vary")).To(Equal("Accept"))
+	Expect(string(body)).To(MatchJSON(`{"name": "foo", "count": 3}`))
+}
+
+func (s *NegotiateSuite) TestCodecs(t sweet.T) {
+	testCases := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"application/json", "application/json", `{"name":"foo","count":3}`},
+		{"application/yaml", "application/yaml", "count: 3\nname: foo\n"},
+		{"application/x-yaml", "application/yaml", "count: 3\nname: foo\n"},
+		{"application/xml", "application/xml", `<negotiatePayload><name>foo</name><count>3</count></negotiatePayload>`},
+		{"text/plain", "text/plain; charset=utf-8", `{foo 3}`},
+	}
+
+	for _, testCase := range testCases {
+		headers, body, err := response.Serialize(negotiate(testCase.accept, negotiatePayload{"foo", 3}))
+		Expect(err).To(BeNil())
+		Expect(headers.Get("Content-Type")).To(Equal(testCase.contentType))
+		Expect(string(body)).To(Equal(testCase.body))
+	}
+}
+
+func (s *NegotiateSuite) TestMessagePack(t sweet.T) {
+	headers, body, err := response.Serialize(negotiate("application/msgpack", negotiatePayload{"foo", 3}))
+	Expect(err).To(BeNil())
+	Expect(headers.Get("Content-Type")).To(Equal("application/msgpack"))
+
+	payload := negotiatePayload{}
+	Expect(msgpack.Unmarshal(body, &payload)).To(BeNil())
+	Expect(payload).To(Equal(negotiatePayload{"foo", 3}))
+}
+
+func (s *NegotiateSuite) TestQualityValues(t sweet.T) {
+	testCases := []struct {
+		accept      string
+		contentType string
+	}{
+		{"application/xml;q=0.5, application/yaml", "application/yaml"},
+		{"application/xml;q=0.9, application/yaml;q=0.8", "application/xml"},
+		{"application/*;q=0.5, application/yaml;q=0.9", "application/yaml"},
+		{"text/plain, application/json;q=0.1", "text/plain; charset=utf-8"},
+		{"text/*, application/json;q=0.1", "application/yaml"},
+		{"*/*", "application/json"},
+		{"*", "application/json"},
+		{"*;q=0.1, application/xml", "application/xml"},
+		{"*/*;q=0.1, application/json;q=0, application/xml;q=0.2", "application/xml"},
+		{"application/json;q=0, */*", "application/yaml"},
+		{"application/xml, application/yaml", "application/yaml"},
+	}
+
+	for _, testCase := range testCases {
+		headers, _, err := response.Serialize(negotiate(testCase.accept, negotiatePayload{"foo", 3}))
+		Expect(err).To(BeNil())
+		Expect(headers.Get("Content-Type")).To(Equal(testCase.contentType), testCase.accept)
+	}
+}
+
+func (s *NegotiateSuite) TestNotAcceptable(t sweet.T) {
+	for _, accept := range []string{"image/png", "application/json;q=0", "text/html, image/*"} {
+		resp := negotiate(accept, negotiatePayload{"foo", 3})
+		Expect(resp.StatusCode()).To(Equal(http.StatusNotAcceptable))
+		Expect(resp.Header("Vary")).To(Equal("Accept"))
+	}
+}
+
+func (s *NegotiateSuite) TestNotAcceptableProblemDetails(t sweet.T) {
+	handler := func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return Negotiate(req, http.StatusOK, negotiatePayload{"foo", 3})
+	}
+
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails())
+	Expect(router.Register("/", &EmptySpec{}, WithMethodHandler("GET", handler))).To(BeNil())
+
+	recorder := serveRequest(router, "GET", "/", map[string]string{"Accept": "image/png"})
+	Expect(recorder.Code).To(Equal(http.StatusNotAcceptable))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal(ProblemContentType))
+	Expect(recorder.HeaderMap.Get("Vary")).To(Equal("Accept"))
+	Expect(recorder.Body.String()).To(ContainSubstring(`"status":406`))
+}
+
+func (s *NegotiateSuite) TestCustomCodecs(t sweet.T) {
+	csv := Codec{
+		ContentType: "text/csv",
+		Marshal: func(value interface{}) ([]byte, error) {
+			payload := value.(negotiatePayload)
+			return []byte(payload.Name + ",3"), nil
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "text/*")
+
+	headers, body, err := response.Serialize(Negotiate(req, http.StatusOK, negotiatePayload{"foo", 3}, JSONCodec, csv))
+	Expect(err).To(BeNil())
+	Expect(headers.Get("Content-Type")).To(Equal("text/csv"))
+	Expect(string(body)).To(Equal("foo,3"))
+}
+
+func (s *NegotiateSuite) TestMarshalError(t sweet.T) {
+	resp := negotiate("application/json", map[string]interface{}{"ch": make(chan int)})
+	Expect(resp.StatusCode()).To(Equal(http.StatusInternalServerError))
+}
+
+//
+//
+
+func negotiate(accept string, value interface{}) response.Response {
+	req, _ := http.NewRequest("GET", "/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	return Negotiate(req, http.StatusCreated, value)
+}
@@ -0,0 +1,316 @@
+package chevron
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type (
+	// registeredPattern is a URL pattern registered to the router along with
+	// the description of the matchers which restrict it.
+	registeredPattern struct {
+		pattern  string
+		matchers routeMatchers
+		segments []patternSegment
+		fold     *foldPattern
+	}
+
+	// routeMatchers is the canonical form of the host, scheme, header, and
+	// query matchers which restrict a route.
+	routeMatchers struct {
+		host    string
+		schemes []string
+		headers []string
+		queries []string
+	}
+
+	// patternSegment is a slash-delimited portion of a URL pattern.
+	patternSegment struct {
+		literal    bool
+		value      string
+		canonical  string
+		defaultVar bool
+		regex      *regexp.Regexp
+	}
+
+	patternRelation int
+)
+
+const (
+	// relationDisjoint indicates that no URL matches both patterns (or
+	// that the relationship could not be determined).
+	relationDisjoint patternRelation = iota
+
+	// relationEqual indicates that both patterns match the same URLs.
+	relationEqual
+
+	// relationSubset indicates that every URL matching the new pattern
+	// also matches the existing pattern.
+	relationSubset
+
+	// relationSuperset indicates that every URL matching the existing
+	// pattern also matches the new pattern.
+	relationSuperset
+
+	// relationOverlap indicates that some, but not all, URLs matching
+	// either pattern also match the other pattern.
+	relationOverlap
+)
+
+const defaultVarPattern = "[^/]+"
+
+// checkOverlap returns a descriptive error if the given URL pattern is
+// ambiguous with or shadowed by a previously registered URL pattern whose
+// matchers are no more restrictive than those of the given pattern (e.g. a
+// route with no matchers shadows a route with the same URL pattern and a
+// host matcher). Patterns that cannot be analyzed are not checked.
+func checkOverlap(patterns []registeredPattern, candidate registeredPattern) error {
+	if candidate.segments == nil {
+		return nil
+	}
+
+	for _, existing := range patterns {
+		if existing.segments == nil || !existing.matchers.covers(candidate.matchers) {
+			continue
+		}
+
+		var reason string
+		switch comparePatterns(existing.segments, candidate.segments) {
+		case relationEqual:
+			if candidate.matchers.covers(existing.matchers) {
+				reason = "is ambiguous with"
+			} else {
+				reason = "is shadowed by"
+			}
+		case relationSubset:
+			reason = "is shadowed by"
+		case relationOverlap:
+			reason = "overlaps"
+		default:
+			continue
+		}
+
+		return fmt.Errorf(
+			"url pattern `%s` %s previously registered url pattern `%s` (use WithAllowOverlap to permit)",
+			candidate.pattern,
+			reason,
+			existing.pattern,
+		)
+	}
+
+	return nil
+}
+
+// covers determines if every request matched by the other matchers is also
+// matched by these matchers. A header matcher with an empty value covers any
+// header matcher with the same name.
+func (m routeMatchers) covers(other routeMatchers) bool {
+	if m.host != "" && m.host != other.host {
+		return false
+	}
+
+	if len(m.schemes) > 0 && (len(other.schemes) == 0 || !containsAll(m.schemes, other.schemes)) {
+		return false
+	}
+
+	for _, pair := range m.headers {
+		if !containsString(other.headers, pair) && !(strings.HasSuffix(pair, "=") && containsPrefix(other.headers, pair)) {
+			return false
+		}
+	}
+
+	return containsAll(other.queries, m.queries)
+}
+
+// containsAll determines if every one of the given values is in the given set.
+func containsAll(set, values []string) bool {
+	for _, value := range values {
+		if !containsString(set, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsPrefix determines if any of the given values has the given prefix.
+func containsPrefix(values []string, prefix string) bool {
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// comparePatterns determines the relationship between the URLs matched by an
+// existing pattern and a new pattern.
+func comparePatterns(existing, candidate []patternSegment) patternRelation {
+	if len(existing) != len(candidate) {
+		return relationDisjoint
+	}
+
+	relations := map[patternRelation]bool{}
+	for i := range existing {
+		relation := compareSegments(existing[i], candidate[i])
+		if relation == relationDisjoint {
+			return relationDisjoint
+		}
+
+		relations[relation] = true
+	}
+
+	switch {
+	case relations[relationOverlap] || (relations[relationSubset] && relations[relationSuperset]):
+		return relationOverlap
+	case relations[relationSubset]:
+		return relationSubset
+	case relations[relationSuperset]:
+		return relationSuperset
+	default:
+		return relationEqual
+	}
+}
+
+// compareSegments determines the relationship between the segments of an
+// existing pattern and a new pattern. Two segments with distinct custom
+// variable patterns are assumed to be disjoint.
+func compareSegments(existing, candidate patternSegment) patternRelation {
+	switch {
+	case existing.canonical == candidate.canonical:
+		return relationEqual
+	case existing.literal && candidate.literal:
+		return relationDisjoint
+	case candidate.literal:
+		if existing.regex.MatchString(candidate.value) {
+			return relationSubset
+		}
+
+		return relationDisjoint
+	case existing.literal:
+		if candidate.regex.MatchString(existing.value) {
+			return relationSuperset
+		}
+
+		return relationDisjoint
+	case existing.defaultVar:
+		return relationSubset
+	case candidate.defaultVar:
+		return relationSuperset
+	default:
+		return relationDisjoint
+	}
+}
+
+// parsePattern splits the given URL pattern into segments. Returns nil if the
+// pattern contains a variable whose pattern may match a slash, as such a pattern
+// cannot be compared segment-wise.
+func parsePattern(pattern string) []patternSegment {
+	segments := []patternSegment{}
+	for _, part := range splitPattern(pattern) {
+		segment, ok := parseSegment(part)
+		if !ok {
+			return nil
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments
+}
+
+// splitPattern splits the given URL pattern on slashes which do not occur
+// within a variable declaration.
+func splitPattern(pattern string) []string {
+	var (
+		parts = []string{}
+		level = 0
+		start = 0
+	)
+
+	for i, c := range pattern {
+		switch c {
+		case '{':
+			level++
+		case '}':
+			level--
+		case '/':
+			if level == 0 {
+				parts = append(parts, pattern[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, pattern[start:])
+}
+
+// parseSegment creates a segment from a portion of a URL pattern. Returns false
+// if the segment is malformed or contains a variable whose pattern may match a
+// slash.
+func parseSegment(part string) (patternSegment, bool) {
+	if !strings.Contains(part, "{") {
+		return patternSegment{literal: true, value: part, canonical: part}, true
+	}
+
+	var (
+		canonical  strings.Builder
+		expression strings.Builder
+		level      = 0
+		start      = 0
+		vars       = 0
+	)
+
+	expression.WriteString("^")
+
+	for i, c := range part {
+		switch c {
+		case '{':
+			if level == 0 {
+				canonical.WriteString(part[start:i])
+				expression.WriteString(regexp.QuoteMeta(part[start:i]))
+				start = i + 1
+			}
+
+			level++
+		case '}':
+			level--
+
+			if level == 0 {
+				varPattern := defaultVarPattern
+				if parts := strings.SplitN(part[start:i], ":", 2); len(parts) == 2 {
+					varPattern = parts[1]
+				}
+
+				if varRegex, err := regexp.Compile("^(?:" + varPattern + ")$"); err != nil || varRegex.MatchString("/") {
+					return patternSegment{}, false
+				}
+
+				canonical.WriteString("{:" + varPattern + "}")
+				expression.WriteString("(?:" + varPattern + ")")
+				start = i + 1
+				vars++
+			}
+		}
+	}
+
+	if level != 0 {
+		return patternSegment{}, false
+	}
+
+	canonical.WriteString(part[start:])
+	expression.WriteString(regexp.QuoteMeta(part[start:]) + "$")
+
+	regex, err := regexp.Compile(expression.String())
+	if err != nil {
+		return patternSegment{}, false
+	}
+
+	return patternSegment{
+		canonical:  canonical.String(),
+		defaultVar: vars == 1 && canonical.String() == "{:"+defaultVarPattern+"}",
+		regex:      regex,
+	}, true
+}
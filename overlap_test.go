@@ -0,0 +1,92 @@
+package chevron
+
+import (
+	"github.com/aphistic/sweet"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type OverlapSuite struct{}
+
+func (s *OverlapSuite) TestComparePatterns(t sweet.T) {
+	testCases := []struct {
+		existing string
+		pattern  string
+		relation patternRelation
+	}{
+		{"/users/{id}", "/users/{name}", relationEqual},
+		{"/users/{id:[0-9]+}", "/users/{name:[0-9]+}", relationEqual},
+		{"/users/{id}", "/users/me", relationSubset},
+		{"/users/{id:[0-9]+}", "/users/123", relationSubset},
+		{"/users/{id:[0-9]+}", "/users/me", relationDisjoint},
+		{"/users/{id:[0-9]+}", "/users/{name:[a-z]+}", relationDisjoint},
+		{"/users/{id:[0-9]+}", "/users/{name}", relationSuperset},
+		{"/users/me", "/users/{id}", relationSuperset},
+		{"/users/{id}", "/users/{id}.json", relationSubset},
+		{"/users/{id}/posts", "/users/me/{post}", relationOverlap},
+		{"/users/{id}", "/users/{id}/posts", relationDisjoint},
+		{"/users", "/users/", relationDisjoint},
+		{"/users", "/posts", relationDisjoint},
+	}
+
+	for _, testCase := range testCases {
+		relation := comparePatterns(parsePattern(testCase.existing), parsePattern(testCase.pattern))
+		Expect(relation).To(Equal(testCase.relation), "%s vs %s", testCase.existing, testCase.pattern)
+	}
+}
+
+func (s *OverlapSuite) TestParsePatternUnanalyzable(t sweet.T) {
+	Expect(parsePattern("/files/{path:.*}")).To(BeNil())
+	Expect(parsePattern("/files/{path")).To(BeNil())
+	Expect(parsePattern("/files/{id:[0-9]{2,3}}")).NotTo(BeNil())
+}
+
+func (s *OverlapSuite) TestRegisterOverlap(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	Expect(router.Register("/users/me", &EmptySpec{})).To(BeNil())
+	Expect(router.Register("/users/{id}", &EmptySpec{})).To(BeNil())
+
+	err := router.Register("/users/{name}", &EmptySpec{})
+	Expect(err).To(MatchError("url pattern `/users/{name}` is ambiguous with previously registered url pattern `/users/{id}` (use WithAllowOverlap to permit)"))
+
+	err = router.Register("/users/{id}", &EmptySpec{}, WithHost("api.example.com"))
+	Expect(err).To(MatchError("url pattern `/users/{id}` is shadowed by previously registered url pattern `/users/{id}` (use WithAllowOverlap to permit)"))
+
+	err = router.Group("/users").Register("/you", &EmptySpec{})
+	Expect(err).To(MatchError("url pattern `/users/you` is shadowed by previously registered url pattern `/users/{id}` (use WithAllowOverlap to permit)"))
+
+	Expect(router.Register("/users/{id}/posts", &EmptySpec{})).To(BeNil())
+	err = router.RegisterHandler("/users/me/{post}", nil)
+	Expect(err).To(MatchError("url pattern `/users/me/{post}` overlaps previously registered url pattern `/users/{id}/posts` (use WithAllowOverlap to permit)"))
+
+	Expect(router.Register("/users/you", &EmptySpec{}, WithAllowOverlap())).To(BeNil())
+}
+
+func (s *OverlapSuite) TestRegisterOverlapMatchers(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	// More restrictive matchers registered first are not shadowed
+	Expect(router.Register("/posts/{id}", &EmptySpec{}, WithHost("api.example.com"), WithSchemes("https"))).To(BeNil())
+	Expect(router.Register("/posts/{id}", &EmptySpec{}, WithHost("api.example.com"))).To(BeNil())
+	Expect(router.Register("/posts/{id}", &EmptySpec{}, WithHost("www.example.com"))).To(BeNil())
+
+	err := router.Register("/posts/{id}", &EmptySpec{}, WithHost("API.example.com"), WithSchemes("http"))
+	Expect(err).To(MatchError("url pattern `/posts/{id}` is shadowed by previously registered url pattern `/posts/{id}` (use WithAllowOverlap to permit)"))
+
+	err = router.Register("/posts/me", &EmptySpec{}, WithHost("www.example.com"), WithQueries("v", "1"))
+	Expect(err).To(MatchError("url pattern `/posts/me` is shadowed by previously registered url pattern `/posts/{id}` (use WithAllowOverlap to permit)"))
+
+	Expect(router.Register("/comments", &EmptySpec{}, WithHeaders("X-Version", ""))).To(BeNil())
+	err = router.Register("/comments", &EmptySpec{}, WithHeaders("x-version", "2", "X-Beta", "1"))
+	Expect(err).To(MatchError("url pattern `/comments` is shadowed by previously registered url pattern `/comments` (use WithAllowOverlap to permit)"))
+	Expect(router.Register("/comments", &EmptySpec{}, WithHeaders("X-Beta", "1"))).To(BeNil())
+}
@@ -0,0 +1,110 @@
+package chevron
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	"github.com/google/uuid"
+)
+
+type (
+	// PathParamError is the error supplied to the router's bad request
+	// handler when a path parameter cannot be converted to the requested
+	// type.
+	PathParamError struct {
+		Name  string
+		Value string
+		Err   error
+	}
+
+	tokenPathParams string
+)
+
+// TokenPathParams is the unique token to which the path parameters matched
+// by the router are written to the request context.
+var TokenPathParams = tokenPathParams("chevron.path_params")
+
+// Error returns a description of the malformed path parameter.
+func (e *PathParamError) Error() string {
+	return fmt.Sprintf("invalid path parameter `%s` (%s)", e.Name, e.Err.Error())
+}
+
+// PathParams retrieves all path parameters matched by the router from the
+// given context. If no path parameters are registered with this context,
+// an empty map is returned.
+func PathParams(ctx context.Context) map[string]string {
+	if val, ok := ctx.Value(TokenPathParams).(map[string]string); ok {
+		return val
+	}
+
+	return map[string]string{}
+}
+
+// PathParam retrieves the value of the named path parameter from the given
+// context. If the URL pattern does not declare the parameter, the empty
+// string is returned.
+func PathParam(ctx context.Context, name string) string {
+	return PathParams(ctx)[name]
+}
+
+// PathParamInt64 retrieves the value of the named path parameter from the
+// given context as a 64-bit integer. If the value cannot be parsed, the
+// response of the router's bad request handler is returned and should be
+// returned unmodified by the calling handler.
+func PathParamInt64(ctx context.Context, req *http.Request, logger nacelle.Logger, name string) (int64, response.Response) {
+	value := PathParam(ctx, name)
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, badPathParam(ctx, req, logger, name, value, fmt.Errorf("not an integer"))
+	}
+
+	return parsed, nil
+}
+
+// PathParamUUID retrieves the value of the named path parameter from the
+// given context as a UUID. If the value cannot be parsed, the response of
+// the router's bad request handler is returned and should be returned
+// unmodified by the calling handler.
+func PathParamUUID(ctx context.Context, req *http.Request, logger nacelle.Logger, name string) (uuid.UUID, response.Response) {
+	value := PathParam(ctx, name)
+
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, badPathParam(ctx, req, logger, name, value, fmt.Errorf("not a uuid"))
+	}
+
+	return parsed, nil
+}
+
+// PathParamEnum retrieves the value of the named path parameter from the
+// given context and ensures that it is one of the given values. If the value
+// is not permitted, the response of the router's bad request handler is
+// returned and should be returned unmodified by the calling handler.
+func PathParamEnum(ctx context.Context, req *http.Request, logger nacelle.Logger, name string, values ...string) (string, response.Response) {
+	value := PathParam(ctx, name)
+
+	for _, candidate := range values {
+		if value == candidate {
+			return value, nil
+		}
+	}
+
+	return "", badPathParam(ctx, req, logger, name, value, fmt.Errorf("expected one of %v", values))
+}
+
+func badPathParam(ctx context.Context, req *http.Request, logger nacelle.Logger, name, value string, err error) response.Response {
+	return GetBadRequestHandler(ctx)(ctx, req, logger, &PathParamError{
+		Name:  name,
+		Value: value,
+		Err:   err,
+	})
+}
+
+func setPathParams(ctx context.Context, params map[string]string) context.Context {
+	return context.WithValue(ctx, TokenPathParams, params)
+}
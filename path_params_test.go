@@ -0,0 +1,158 @@
+package chevron
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type PathParamsSuite struct{}
+
+func (s *PathParamsSuite) TestPathParam(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+		params    map[string]string
+	)
+
+	spec := &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		params = PathParams(ctx)
+		return response.JSON(PathParam(ctx, "id"))
+	}}
+
+	Expect(router.Register("/users/{id}/posts/{post}", spec)).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users/123/posts/abc", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`"123"`))
+	Expect(params).To(Equal(map[string]string{"id": "123", "post": "abc"}))
+}
+
+func (s *PathParamsSuite) TestPathParamMissing(t sweet.T) {
+	Expect(PathParam(context.Background(), "id")).To(BeEmpty())
+	Expect(PathParams(context.Background())).To(BeEmpty())
+}
+
+func (s *PathParamsSuite) TestPathParamInt64(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	spec := &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		id, resp := PathParamInt64(ctx, req, logger, "id")
+		if resp != nil {
+			return resp
+		}
+
+		return response.JSON(id)
+	}}
+
+	Expect(router.Register("/users/{id}", spec)).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users/123", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`123`))
+
+	req, _ = http.NewRequest("GET", "/users/foo", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+}
+
+func (s *PathParamsSuite) TestPathParamUUID(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	spec := &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		id, resp := PathParamUUID(ctx, req, logger, "id")
+		if resp != nil {
+			return resp
+		}
+
+		return response.JSON(id.String())
+	}}
+
+	Expect(router.Register("/users/{id}", spec)).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users/6ba7b810-9dad-11d1-80b4-00c04fd430c8", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`))
+
+	req, _ = http.NewRequest("GET", "/users/123", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+}
+
+func (s *PathParamsSuite) TestPathParamEnum(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		errors    = []error{}
+	)
+
+	badRequestHandler := func(ctx context.Context, req *http.Request, logger nacelle.Logger, err error) response.Response {
+		errors = append(errors, err)
+		return response.Empty(http.StatusTeapot)
+	}
+
+	router := NewRouter(container, logger, WithBadRequestHandler(badRequestHandler))
+
+	spec := &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		kind, resp := PathParamEnum(ctx, req, logger, "kind", "cats", "dogs")
+		if resp != nil {
+			return resp
+		}
+
+		return response.JSON(kind)
+	}}
+
+	Expect(router.Register("/animals/{kind}", spec)).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/animals/dogs", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`"dogs"`))
+
+	req, _ = http.NewRequest("GET", "/animals/birds", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusTeapot))
+	Expect(errors).To(HaveLen(1))
+	Expect(errors[0]).To(Equal(&PathParamError{
+		Name:  "kind",
+		Value: "birds",
+		Err:   fmt.Errorf("expected one of [cats dogs]"),
+	}))
+}
+
+//
+//
+
+type HandlerSpec struct {
+	*EmptySpec
+	handler Handler
+}
+
+func (s *HandlerSpec) Get(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return s.handler(ctx, req, logger)
+}
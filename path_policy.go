@@ -0,0 +1,260 @@
+package chevron
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type (
+	// PathPolicy determines how the router treats a request path that does
+	// not match a registered URL pattern, but whose normalized form does.
+	PathPolicy int
+
+	// foldPattern matches a request path against a URL pattern without
+	// regard to the case of the pattern's literal text.
+	foldPattern struct {
+		regex    *regexp.Regexp
+		literals map[string]string
+	}
+)
+
+const (
+	// PathPolicyStrict does not normalize the request path.
+	PathPolicyStrict PathPolicy = iota
+
+	// PathPolicyRedirect redirects the request to the normalized path. GET
+	// and HEAD requests are redirected with a 301; all other requests are
+	// redirected with a 308 so that the method and body are preserved.
+	PathPolicyRedirect
+
+	// PathPolicyMatch serves the request as if it were made to the normalized
+	// path.
+	PathPolicyMatch
+)
+
+// serveMux dispatches the request to the mux after normalizing its path
+// according to the router's path policies.
+func (r *router) serveMux(w http.ResponseWriter, req *http.Request) {
+	normalized, redirect := r.normalizePath(req)
+	if normalized == req.URL.Path {
+		r.mux.ServeHTTP(w, req)
+		return
+	}
+
+	url := *req.URL
+	url.Path = normalized
+	url.RawPath = ""
+
+	if redirect {
+		status := http.StatusPermanentRedirect
+		if req.Method == http.MethodGet || req.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+
+		w.Header().Set("Location", url.String())
+		w.WriteHeader(status)
+		return
+	}
+
+	rewritten := req.WithContext(req.Context())
+	rewritten.URL = &url
+	r.mux.ServeHTTP(w, rewritten)
+}
+
+// normalizePath returns the normalized form of the request path and whether
+// or not the request should be redirected to that path. Duplicate slashes and
+// dot segments are cleaned first. Then, if the path does not match a registered
+// URL pattern, the path with its trailing slash toggled and the case-folded
+// forms of both paths are tried in turn.
+func (r *router) normalizePath(req *http.Request) (string, bool) {
+	var (
+		root     = r.root
+		original = req.URL.Path
+		redirect = false
+	)
+
+	if root.cleanPathPolicy != PathPolicyStrict && req.Method != http.MethodConnect {
+		if cleaned := cleanPath(original); cleaned != original {
+			original = cleaned
+			redirect = root.cleanPathPolicy == PathPolicyRedirect
+		}
+	}
+
+	candidates := []string{original}
+	if root.trailingSlashPolicy != PathPolicyStrict && original != "/" {
+		candidates = append(candidates, toggleTrailingSlash(original))
+	}
+
+	for i, candidate := range candidates {
+		normalized := candidate
+		if root.caseInsensitivePolicy != PathPolicyStrict && !r.matchesPath(req, normalized) {
+			normalized = r.foldPath(normalized)
+		}
+
+		if !r.matchesPath(req, normalized) {
+			continue
+		}
+
+		if i > 0 && root.trailingSlashPolicy == PathPolicyRedirect {
+			redirect = true
+		}
+
+		if normalized != candidate && root.caseInsensitivePolicy == PathPolicyRedirect {
+			redirect = true
+		}
+
+		return normalized, redirect
+	}
+
+	return original, redirect
+}
+
+// matchesPath determines if the request would match a registered route if
+// it were made to the given path.
+func (r *router) matchesPath(req *http.Request, path string) bool {
+	url := *req.URL
+	url.Path = path
+	url.RawPath = ""
+
+	clone := req.WithContext(req.Context())
+	clone.URL = &url
+
+	match := &mux.RouteMatch{}
+	return r.mux.Match(clone, match) && match.MatchErr != mux.ErrNotFound
+}
+
+// foldPath replaces the literal text of the request path with the literal
+// text of the first registered URL pattern that matches the path without
+// regard to case. The path is returned unchanged if there is no such pattern.
+func (r *router) foldPath(requestPath string) string {
+	for _, pattern := range r.root.patterns {
+		if pattern.fold == nil {
+			continue
+		}
+
+		if folded, ok := pattern.fold.apply(requestPath); ok {
+			return folded
+		}
+	}
+
+	return requestPath
+}
+
+// compileFoldPattern creates a fold pattern from the given URL pattern.
+// Returns nil if the pattern contains a malformed variable.
+func compileFoldPattern(pattern string) *foldPattern {
+	var (
+		expression strings.Builder
+		literals   = map[string]string{}
+		level      = 0
+		start      = 0
+	)
+
+	addLiteral := func(literal string) {
+		if literal != "" {
+			name := fmt.Sprintf("literal%d", len(literals))
+			literals[name] = literal
+			expression.WriteString(fmt.Sprintf("(?P<%s>(?i:%s))", name, regexp.QuoteMeta(literal)))
+		}
+	}
+
+	expression.WriteString("^")
+
+	for i, c := range pattern {
+		switch c {
+		case '{':
+			if level == 0 {
+				addLiteral(pattern[start:i])
+				start = i + 1
+			}
+
+			level++
+		case '}':
+			level--
+
+			if level == 0 {
+				varPattern := defaultVarPattern
+				if parts := strings.SplitN(pattern[start:i], ":", 2); len(parts) == 2 {
+					varPattern = parts[1]
+				}
+
+				expression.WriteString("(?:" + varPattern + ")")
+				start = i + 1
+			}
+		}
+	}
+
+	if level != 0 {
+		return nil
+	}
+
+	addLiteral(pattern[start:])
+	expression.WriteString("$")
+
+	regex, err := regexp.Compile(expression.String())
+	if err != nil {
+		return nil
+	}
+
+	return &foldPattern{regex: regex, literals: literals}
+}
+
+// apply returns the given path with its literal text replaced by the literal
+// text of the pattern. Returns false if the pattern does not match the path.
+func (p *foldPattern) apply(requestPath string) (string, bool) {
+	indices := p.regex.FindStringSubmatchIndex(requestPath)
+	if indices == nil {
+		return "", false
+	}
+
+	var (
+		folded strings.Builder
+		last   = 0
+	)
+
+	for i, name := range p.regex.SubexpNames() {
+		literal, ok := p.literals[name]
+		if !ok || indices[2*i] < 0 {
+			continue
+		}
+
+		folded.WriteString(requestPath[last:indices[2*i]])
+		folded.WriteString(literal)
+		last = indices[2*i+1]
+	}
+
+	folded.WriteString(requestPath[last:])
+	return folded.String(), true
+}
+
+// cleanPath returns the canonical form of the given path, eliminating
+// duplicate slashes and dot segments but retaining a trailing slash.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	if p[0] != '/' {
+		p = "/" + p
+	}
+
+	cleaned := path.Clean(p)
+	if p[len(p)-1] == '/' && cleaned != "/" {
+		cleaned += "/"
+	}
+
+	return cleaned
+}
+
+func toggleTrailingSlash(p string) string {
+	if strings.HasSuffix(p, "/") {
+		return strings.TrimSuffix(p, "/")
+	}
+
+	return p + "/"
+}
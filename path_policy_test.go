@@ -0,0 +1,178 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type PathPolicySuite struct{}
+
+func (s *PathPolicySuite) TestTrailingSlashStrict(t sweet.T) {
+	router := newPathPolicyRouter()
+
+	Expect(serveRequest(router, "GET", "/users", nil).Code).To(Equal(http.StatusOK))
+	Expect(serveRequest(router, "GET", "/users/", nil).Code).To(Equal(http.StatusNotFound))
+	Expect(serveRequest(router, "GET", "/teams/", nil).Code).To(Equal(http.StatusOK))
+	Expect(serveRequest(router, "GET", "/teams", nil).Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *PathPolicySuite) TestTrailingSlashRedirect(t sweet.T) {
+	router := newPathPolicyRouter(WithTrailingSlashPolicy(PathPolicyRedirect))
+
+	recorder := serveRequest(router, "GET", "/users/?limit=5", nil)
+	Expect(recorder.Code).To(Equal(http.StatusMovedPermanently))
+	Expect(recorder.HeaderMap.Get("Location")).To(Equal("/users?limit=5"))
+
+	recorder = serveRequest(router, "POST", "/teams", nil)
+	Expect(recorder.Code).To(Equal(http.StatusPermanentRedirect))
+	Expect(recorder.HeaderMap.Get("Location")).To(Equal("/teams/"))
+
+	Expect(serveRequest(router, "GET", "/missing/", nil).Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *PathPolicySuite) TestTrailingSlashMatch(t sweet.T) {
+	router := newPathPolicyRouter(WithTrailingSlashPolicy(PathPolicyMatch))
+
+	recorder := serveRequest(router, "GET", "/users/", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`"/users"`))
+
+	recorder = serveRequest(router, "GET", "/users/42/", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`"42"`))
+
+	Expect(serveRequest(router, "GET", "/teams", nil).Code).To(Equal(http.StatusOK))
+}
+
+func (s *PathPolicySuite) TestCleanPathDefault(t sweet.T) {
+	router := newPathPolicyRouter()
+
+	recorder := serveRequest(router, "GET", "/./users//42/../42", nil)
+	Expect(recorder.Code).To(Equal(http.StatusMovedPermanently))
+	Expect(recorder.HeaderMap.Get("Location")).To(Equal("/users/42"))
+
+	recorder = serveRequest(router, "DELETE", "/users//42", nil)
+	Expect(recorder.Code).To(Equal(http.StatusPermanentRedirect))
+	Expect(recorder.HeaderMap.Get("Location")).To(Equal("/users/42"))
+}
+
+func (s *PathPolicySuite) TestCleanPathStrict(t sweet.T) {
+	router := newPathPolicyRouter(WithCleanPathPolicy(PathPolicyStrict))
+
+	Expect(serveRequest(router, "GET", "/users//", nil).Code).To(Equal(http.StatusNotFound))
+	Expect(serveRequest(router, "GET", "/users", nil).Code).To(Equal(http.StatusOK))
+}
+
+func (s *PathPolicySuite) TestCleanPathMatch(t sweet.T) {
+	router := newPathPolicyRouter(WithCleanPathPolicy(PathPolicyMatch))
+
+	recorder := serveRequest(router, "GET", "/users//42/.", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`"42"`))
+}
+
+func (s *PathPolicySuite) TestCaseInsensitiveRedirect(t sweet.T) {
+	router := newPathPolicyRouter(WithCaseInsensitivePolicy(PathPolicyRedirect))
+
+	recorder := serveRequest(router, "GET", "/USERS/AbC", nil)
+	Expect(recorder.Code).To(Equal(http.StatusMovedPermanently))
+	Expect(recorder.HeaderMap.Get("Location")).To(Equal("/users/AbC"))
+
+	recorder = serveRequest(router, "GET", "/Files/Report.JSON", nil)
+	Expect(recorder.Code).To(Equal(http.StatusMovedPermanently))
+	Expect(recorder.HeaderMap.Get("Location")).To(Equal("/files/Report.json"))
+}
+
+func (s *PathPolicySuite) TestCaseInsensitiveMatch(t sweet.T) {
+	router := newPathPolicyRouter(WithCaseInsensitivePolicy(PathPolicyMatch))
+
+	recorder := serveRequest(router, "GET", "/Users/AbC", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`"AbC"`))
+
+	Expect(serveRequest(router, "GET", "/uSeRs", nil).Code).To(Equal(http.StatusOK))
+	Expect(serveRequest(router, "GET", "/accounts", nil).Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *PathPolicySuite) TestCaseInsensitiveGroup(t sweet.T) {
+	var (
+		root  = newPathPolicyRouter(WithCaseInsensitivePolicy(PathPolicyMatch))
+		group = root.Group("/api").(*router)
+	)
+
+	Expect(group.Register("/posts/{id}", &HandlerSpec{handler: okHandler})).To(BeNil())
+	Expect(serveRequest(root, "GET", "/API/Posts/AbC", nil).Code).To(Equal(http.StatusOK))
+
+	// Groups fold paths against the patterns of the entire router
+	Expect(group.foldPath("/USERS/AbC")).To(Equal("/users/AbC"))
+	Expect(group.foldPath("/API/Posts/AbC")).To(Equal("/api/posts/AbC"))
+}
+
+func (s *PathPolicySuite) TestCombinedPolicies(t sweet.T) {
+	router := newPathPolicyRouter(
+		WithTrailingSlashPolicy(PathPolicyMatch),
+		WithCaseInsensitivePolicy(PathPolicyRedirect),
+	)
+
+	recorder := serveRequest(router, "GET", "/USERS/abc/", nil)
+	Expect(recorder.Code).To(Equal(http.StatusMovedPermanently))
+	Expect(recorder.HeaderMap.Get("Location")).To(Equal("/users/abc"))
+
+	recorder = serveRequest(router, "GET", "/users/abc/", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+}
+
+func (s *PathPolicySuite) TestRegisterHandler(t sweet.T) {
+	router := newPathPolicyRouter(
+		WithTrailingSlashPolicy(PathPolicyMatch),
+		WithCaseInsensitivePolicy(PathPolicyMatch),
+	)
+
+	Expect(router.RegisterHandler("/Raw", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))).To(BeNil())
+
+	Expect(serveRequest(router, "GET", "/raw/", nil).Code).To(Equal(http.StatusAccepted))
+}
+
+func (s *PathPolicySuite) TestPipeline(t sweet.T) {
+	router := newPathPolicyRouter(WithTrailingSlashPolicy(PathPolicyRedirect))
+
+	Expect(router.Use(MiddlewareFunc(func(h Handler) (Handler, error) {
+		return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+			return h(ctx, req, logger).SetHeader("X-Pipeline", "true")
+		}, nil
+	}))).To(BeNil())
+
+	recorder := serveRequest(router, "GET", "/users/", nil)
+	Expect(recorder.Code).To(Equal(http.StatusMovedPermanently))
+	Expect(recorder.HeaderMap.Get("Location")).To(Equal("/users"))
+	Expect(recorder.HeaderMap.Get("X-Pipeline")).To(Equal("true"))
+}
+
+//
+//
+
+func newPathPolicyRouter(configs ...RouterConfigFunc) Router {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), configs...)
+
+	pathHandler := func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return response.JSON(req.URL.Path)
+	}
+
+	idHandler := func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return response.JSON(PathParam(ctx, "id"))
+	}
+
+	Expect(router.Register("/users", &HandlerSpec{handler: pathHandler})).To(BeNil())
+
+	Expect(router.Register("/users/{id}", &HandlerSpec{handler: idHandler}, WithMethodHandler("DELETE", idHandler))).To(BeNil())
+	Expect(router.Register("/teams/", &HandlerSpec{handler: pathHandler}, WithMethodHandler("POST", pathHandler))).To(BeNil())
+	Expect(router.Register("/files/{name}.json", &HandlerSpec{handler: pathHandler})).To(BeNil())
+	return router
+}
@@ -0,0 +1,89 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	"github.com/gorilla/mux"
+)
+
+type (
+	// pipelineSlot carries the context and logger decorated by the router's
+	// pipeline middleware to the handler matched by the mux, and carries the
+	// response of that handler back to the pipeline.
+	pipelineSlot struct {
+		base   context.Context
+		ctx    context.Context
+		logger nacelle.Logger
+		resp   response.Response
+	}
+
+	// requestContext carries the values of a router's base context as well
+	// as the cancellation, deadline, and values of a request's context. The
+	// values of the base context take precedence.
+	requestContext struct {
+		context.Context
+		base context.Context
+	}
+
+	tokenPipelineSlot string
+)
+
+var tokenSlot = tokenPipelineSlot("chevron.pipeline_slot")
+
+// dispatch is the innermost handler of the router's pipeline. It invokes the
+// handler registered to the request URL. If that handler was created by this
+// router, its response is returned directly. Otherwise, the output of the
+// handler is captured and converted into a response.
+func (r *router) dispatch(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	slot := &pipelineSlot{
+		base:   r.baseCtx,
+		ctx:    ctx,
+		logger: logger,
+	}
+
+	resp := captureResponse(http.HandlerFunc(r.serveMux), req.WithContext(context.WithValue(req.Context(), tokenSlot, slot)))
+
+	if slot.resp != nil {
+		return slot.resp
+	}
+
+	return resp
+}
+
+// convert creates an http.Handler that invokes the given handler with a
+// context derived from both the given context and the request's context,
+// and the given logger. If the request is being dispatched by the pipeline
+// of the router owning the given context, the handler is instead invoked with
+// the context and logger decorated by the pipeline, and its response is handed
+// back to the pipeline rather than being written. Responses are decorated with
+// problem details before being written if the router was so configured.
+func convert(ctx context.Context, handler Handler, logger nacelle.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if slot, ok := req.Context().Value(tokenSlot).(*pipelineSlot); ok && slot.base == ctx {
+			slot.resp = handler(setPathParams(slot.ctx, mux.Vars(req)), req, slot.logger)
+			return
+		}
+
+		resp := handler(setPathParams(withRequestContext(ctx, req), mux.Vars(req)), req, logger)
+		decorateProblem(ctx, req, resp).WriteTo(w)
+	})
+}
+
+// withRequestContext creates a context carrying the values of the given base
+// context which is canceled along with the context of the given request.
+func withRequestContext(base context.Context, req *http.Request) context.Context {
+	return requestContext{Context: req.Context(), base: base}
+}
+
+// Value returns the value associated with the given key in the base context,
+// falling back to the request's context.
+func (c requestContext) Value(key interface{}) interface{} {
+	if val := c.base.Value(key); val != nil {
+		return val
+	}
+
+	return c.Context.Value(key)
+}
@@ -0,0 +1,187 @@
+package chevron
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/efritz/response"
+)
+
+type (
+	// ProblemDetails is the body of an RFC 7807 problem details response.
+	// The values of Extensions are serialized as additional members of the
+	// problem object.
+	ProblemDetails struct {
+		Type       string
+		Title      string
+		Status     int
+		Detail     string
+		Instance   string
+		Extensions map[string]interface{}
+	}
+
+	// ProblemConfigFunc is a function used to configure problem details
+	// responses.
+	ProblemConfigFunc func(*problemOptions)
+
+	// ProblemExtensionFunc computes the value of an extension member of a
+	// problem details response for the given request. A nil value omits the
+	// member from the response.
+	ProblemExtensionFunc func(*http.Request) interface{}
+
+	problemOptions struct {
+		extensions map[string]ProblemExtensionFunc
+		convert    bool
+	}
+
+	// problemBody replaces the empty body of a response with the serialized
+	// problem details once the original body has been written.
+	problemBody struct {
+		w    io.Writer
+		body []byte
+	}
+
+	tokenProblemOptions string
+)
+
+// ProblemContentType is the content type of a problem details response.
+const ProblemContentType = "application/problem+json"
+
+var tokenProblems = tokenProblemOptions("chevron.problem_details")
+
+// WithProblemExtension registers an extension member which is added to
+// every problem details response created by the router.
+func WithProblemExtension(name string, f ProblemExtensionFunc) ProblemConfigFunc {
+	return func(o *problemOptions) { o.extensions[name] = f }
+}
+
+// WithProblemConversion causes the router to also replace the responses of
+// user handlers (and of user-supplied middleware response factories) that have
+// an error status and an empty body with a problem details response. By default,
+// only the default responses of the router and of the middleware package are
+// problem details.
+func WithProblemConversion() ProblemConfigFunc {
+	return func(o *problemOptions) { o.convert = true }
+}
+
+// MarshalJSON serializes the problem details and its extension members
+// into a single JSON object.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	members := map[string]interface{}{}
+	for name, value := range p.Extensions {
+		members[name] = value
+	}
+
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+
+	return json.Marshal(members)
+}
+
+func getProblemOptions(ctx context.Context) (*problemOptions, bool) {
+	options, ok := ctx.Value(tokenProblems).(*problemOptions)
+	return options, ok
+}
+
+func setProblemOptions(ctx context.Context, options *problemOptions) context.Context {
+	return context.WithValue(ctx, tokenProblems, options)
+}
+
+// response creates a problem details response describing the given status.
+// The instance of the problem is the request's X-Request-ID header, which
+// is populated by the request ID middleware.
+func (o *problemOptions) response(req *http.Request, status int, detail string, extensions map[string]interface{}) response.Response {
+	body, err := o.body(req, status, detail, extensions)
+	if err != nil {
+		return response.Empty(status)
+	}
+
+	return response.Respond(body).
+		SetStatusCode(status).
+		SetHeader("Content-Type", ProblemContentType)
+}
+
+// body serializes the problem details describing the given status.
+func (o *problemOptions) body(req *http.Request, status int, detail string, extensions map[string]interface{}) ([]byte, error) {
+	problem := ProblemDetails{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     detail,
+		Instance:   req.Header.Get("X-Request-ID"),
+		Extensions: map[string]interface{}{},
+	}
+
+	for name, f := range o.extensions {
+		if value := f(req); value != nil {
+			problem.Extensions[name] = value
+		}
+	}
+
+	for name, value := range extensions {
+		problem.Extensions[name] = value
+	}
+
+	return json.Marshal(problem)
+}
+
+// DefaultResponse creates a bodiless response with the given status, or a
+// problem details response if the router owning the given context was
+// configured to use problem details. This is the response of the router's
+// default handlers, and should be used by middleware for the same purpose.
+func DefaultResponse(ctx context.Context, req *http.Request, status int) response.Response {
+	if options, ok := getProblemOptions(ctx); ok {
+		return options.response(req, status, "", nil)
+	}
+
+	return response.Empty(status)
+}
+
+// decorateProblem replaces the empty body of an error response with problem
+// details if the router owning the given context was configured to convert
+// such responses. The response is modified in place, so its status, headers,
+// and callbacks are retained.
+func decorateProblem(ctx context.Context, req *http.Request, resp response.Response) response.Response {
+	options, ok := getProblemOptions(ctx)
+	if !ok || !options.convert || resp.StatusCode() < 400 || resp.Header("Content-Type") != "" || resp.Header("Content-Length") != "0" {
+		return resp
+	}
+
+	if instance := resp.Header("X-Request-ID"); instance != "" && req.Header.Get("X-Request-ID") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("X-Request-ID", instance)
+	}
+
+	body, err := options.body(req, resp.StatusCode(), "", nil)
+	if err != nil {
+		return resp
+	}
+
+	return resp.
+		SetHeader("Content-Type", ProblemContentType).
+		SetHeader("Content-Length", strconv.Itoa(len(body))).
+		DecorateWriter(func(w io.Writer) io.Writer { return &problemBody{w: w, body: body} })
+}
+
+// Write discards the original (empty) body of the response.
+func (b *problemBody) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+// Close writes the problem details to the underlying writer.
+func (b *problemBody) Close() error {
+	_, err := b.w.Write(b.body)
+	return err
+}
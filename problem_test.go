@@ -0,0 +1,220 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type ProblemSuite struct{}
+
+func (s *ProblemSuite) TestNotFound(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails())
+
+	req, _ := http.NewRequest("GET", "/missing", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal("application/problem+json"))
+	Expect(recorder.Body.String()).To(MatchJSON(`{
+		"type": "about:blank",
+		"title": "Not Found",
+		"status": 404,
+		"instance": "abc123"
+	}`))
+}
+
+func (s *ProblemSuite) TestNotImplemented(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails())
+	Expect(router.Register("/users", &HandlerSpec{handler: okHandler})).To(BeNil())
+
+	req, _ := http.NewRequest("POST", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+	Expect(recorder.HeaderMap.Get("Allow")).To(Equal("GET, HEAD, OPTIONS"))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"type": "about:blank", "title": "Method Not Allowed", "status": 405}`))
+}
+
+func (s *ProblemSuite) TestBadRequest(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails())
+
+	Expect(router.Register("/users/{id}", &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		if _, resp := PathParamInt64(ctx, req, logger, "id"); resp != nil {
+			return resp
+		}
+
+		return response.Empty(http.StatusOK)
+	}})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users/foo", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+	Expect(recorder.Body.String()).To(MatchJSON(`{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "invalid path parameter ` + "`id`" + ` (not an integer)"
+	}`))
+}
+
+func (s *ProblemSuite) TestErrorMapper(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails())
+
+	Expect(router.Register("/users", &HandlerSpec{handler: HandlerE(func(ctx context.Context, req *http.Request, logger nacelle.Logger) (response.Response, error) {
+		return nil, NewValidationError("invalid user", map[string]string{"name": "required"})
+	}).Handle})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal("application/problem+json"))
+	Expect(recorder.Body.String()).To(MatchJSON(`{
+		"type": "about:blank",
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "invalid user",
+		"fields": {"name": "required"}
+	}`))
+}
+
+func (s *ProblemSuite) TestMiddlewareResponse(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails(WithProblemConversion()))
+
+	forbid := MiddlewareFunc(func(h Handler) (Handler, error) {
+		return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+			return response.Empty(http.StatusUnauthorized).SetHeader("WWW-Authenticate", `Basic realm="test"`)
+		}, nil
+	})
+
+	Expect(router.Use(forbid)).To(BeNil())
+	Expect(router.Register("/users", &HandlerSpec{handler: okHandler})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+	Expect(recorder.HeaderMap.Get("WWW-Authenticate")).To(Equal(`Basic realm="test"`))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"type": "about:blank", "title": "Unauthorized", "status": 401}`))
+}
+
+func (s *ProblemSuite) TestInstanceFromResponse(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails(WithProblemConversion()))
+
+	Expect(router.Register("/users", &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return response.Empty(http.StatusConflict).SetHeader("X-Request-ID", "generated")
+	}})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusConflict))
+	Expect(recorder.HeaderMap.Get("X-Request-ID")).To(Equal("generated"))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"type": "about:blank", "title": "Conflict", "status": 409, "instance": "generated"}`))
+}
+
+func (s *ProblemSuite) TestConversionCallbacks(t sweet.T) {
+	var (
+		router = NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails(WithProblemConversion()))
+		errs   = []error{}
+	)
+
+	Expect(router.Register("/users", &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return response.Empty(http.StatusConflict).AddCallback(func(err error) { errs = append(errs, err) })
+	}})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusConflict))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal("application/problem+json"))
+	Expect(recorder.HeaderMap.Get("Content-Length")).To(Equal(strconv.Itoa(recorder.Body.Len())))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"type": "about:blank", "title": "Conflict", "status": 409}`))
+	Expect(errs).To(Equal([]error{nil}))
+}
+
+func (s *ProblemSuite) TestConversionDisabled(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails())
+
+	Expect(router.Register("/users", &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return response.Empty(http.StatusConflict)
+	}})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusConflict))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(BeEmpty())
+	Expect(recorder.Body.String()).To(BeEmpty())
+}
+
+func (s *ProblemSuite) TestExtensions(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails(
+		WithProblemExtension("service", func(req *http.Request) interface{} { return "users" }),
+		WithProblemExtension("path", func(req *http.Request) interface{} { return req.URL.Path }),
+		WithProblemExtension("omitted", func(req *http.Request) interface{} { return nil }),
+		WithProblemExtension("status", func(req *http.Request) interface{} { return "shadowed" }),
+	))
+
+	req, _ := http.NewRequest("GET", "/missing", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Body.String()).To(MatchJSON(`{
+		"type": "about:blank",
+		"title": "Not Found",
+		"status": 404,
+		"service": "users",
+		"path": "/missing"
+	}`))
+}
+
+func (s *ProblemSuite) TestBodiesRetained(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithProblemDetails())
+
+	Expect(router.Register("/users", &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return response.JSON(map[string]string{"error": "custom"}).SetStatusCode(http.StatusConflict)
+	}})).To(BeNil())
+
+	Expect(router.Register("/empty", &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return response.Empty(http.StatusNoContent)
+	}})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusConflict))
+	Expect(recorder.Body.String()).To(MatchJSON(`{"error": "custom"}`))
+
+	req, _ = http.NewRequest("GET", "/empty", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusNoContent))
+	Expect(recorder.Body.String()).To(BeEmpty())
+}
+
+func (s *ProblemSuite) TestDisabled(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+
+	req, _ := http.NewRequest("GET", "/missing", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(BeEmpty())
+	Expect(recorder.Body.String()).To(BeEmpty())
+}
+
+//
+//
+
+func okHandler(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusOK)
+}
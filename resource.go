@@ -2,7 +2,10 @@ package chevron
 
 import (
 	"context"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/efritz/response"
 	"github.com/go-nacelle/nacelle"
@@ -18,15 +21,29 @@ type (
 	}
 
 	resource struct {
-		hm     handlerMap
-		router *router
+		hm         handlerMap
+		router     *router
+		methods    []Method
+		allow      string
+		middleware middlewareTrace
 	}
 )
 
 // Handle invokes the correct handler based on HTTP method, or the router's not
-// implemented handler if no handler for that method is registered.
+// implemented handler if no handler for that method is registered. The Allow
+// header is added to any response with a 405 status code.
 func (r *resource) Handle(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
-	if method, ok := methodMap[req.Method]; ok {
+	resp := r.handle(ctx, req, logger)
+
+	if resp.StatusCode() == http.StatusMethodNotAllowed && resp.Header("Allow") == "" {
+		resp.SetHeader("Allow", r.allow)
+	}
+
+	return resp
+}
+
+func (r *resource) handle(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	if method, ok := lookupMethod(req.Method); ok {
 		if handler, ok := r.hm[method]; ok {
 			return handler(ctx, req, logger)
 		}
@@ -34,3 +51,54 @@ func (r *resource) Handle(ctx context.Context, req *http.Request, logger nacelle
 
 	return r.router.notImplementedHandler(ctx, req, logger)
 }
+
+// describe adds the methods implemented by the resource and the names of
+// the middleware applied to each method to the given route description.
+func (r *resource) describe(info RouteInfo) RouteInfo {
+	var (
+		methods    = make([]string, 0, len(r.methods))
+		middleware = map[string][]string{}
+	)
+
+	for _, method := range r.methods {
+		methods = append(methods, method.String())
+
+		if names := r.middleware[method]; len(names) > 0 {
+			middleware[method.String()] = names
+		}
+	}
+
+	info.Methods = methods
+	info.Middleware = middleware
+	return info
+}
+
+// makeHeadHandler creates a handler that responds to a HEAD request with
+// the status and headers of the given GET handler. The body of the response
+// is discarded, but its Content-Length header is preserved.
+func makeHeadHandler(get Handler) Handler {
+	return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return get(ctx, req, logger).DecorateWriter(func(w io.Writer) io.Writer {
+			return ioutil.Discard
+		})
+	}
+}
+
+// makeOptionsHandler creates a handler that responds to an OPTIONS request
+// with the given value of the Allow header.
+func makeOptionsHandler(allow string) Handler {
+	return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return response.Empty(http.StatusNoContent).SetHeader("Allow", allow)
+	}
+}
+
+// makeAllowHeader creates the value of the Allow header for a resource
+// implementing the given methods.
+func makeAllowHeader(methods []Method) string {
+	names := make([]string, 0, len(methods))
+	for _, method := range methods {
+		names = append(names, method.String())
+	}
+
+	return strings.Join(names, ", ")
+}
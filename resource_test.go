@@ -1,6 +1,7 @@
 package chevron
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/aphistic/sweet"
@@ -33,7 +34,7 @@ func (s *ResourceSuite) TestHandle(t sweet.T) {
 			expected = http.StatusMethodNotAllowed
 		}
 
-		Expect(r.Handle(nil, makeEmptyRequest(method), nil).StatusCode()).To(Equal(expected))
+		Expect(r.Handle(context.Background(), makeEmptyRequest(method), nil).StatusCode()).To(Equal(expected))
 	}
 }
 
@@ -0,0 +1,326 @@
+package chevron
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/go-nacelle/nacelle"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// routeFileParser converts the nodes of a route file into resource
+	// registrations, resolving names via a route registry.
+	routeFileParser struct {
+		filename string
+		registry *RouteRegistry
+	}
+
+	// routeFileEntry is a resource registration read from a route file.
+	routeFileEntry struct {
+		node    *yaml.Node
+		pattern string
+		spec    ResourceSpec
+		configs []RouteConfig
+	}
+)
+
+// NewRouteFileInitializer creates a RouteInitializer that registers the
+// routes declared in the given YAML or JSON file. Resource specs and
+// middleware are referenced by the names under which they are registered
+// to the given registry. A route file has the following form.
+//
+//	routes:
+//	  - pattern: /users/{id}
+//	    spec: users
+//	    name: user
+//	    middleware:
+//	      - request-id
+//	      - name: cache
+//	        params:
+//	          ttl: 30s
+//	    methods:
+//	      POST:
+//	        - name: schema
+//	          params:
+//	            path: schemas/user.json
+//
+// Middleware listed under `middleware` applies to all methods of the route
+// and wraps the middleware listed under `methods`. Within a list, the first
+// middleware is the outermost.
+func NewRouteFileInitializer(filename string, registry *RouteRegistry) RouteInitializer {
+	return RouteInitializerFunc(func(config nacelle.Config, router Router) error {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("failed to read route file %s (%s)", filename, err)
+		}
+
+		return RegisterRoutes(router, registry, filename, data)
+	})
+}
+
+// RegisterRoutes registers the routes declared in the given route file
+// content (see NewRouteFileInitializer). The filename is used only to give
+// context to errors. No routes are registered if the content refers to an
+// unknown resource spec, middleware, or method, or if a middleware cannot
+// be constructed from its parameters.
+func RegisterRoutes(router Router, registry *RouteRegistry, filename string, data []byte) error {
+	parser := &routeFileParser{
+		filename: filename,
+		registry: registry,
+	}
+
+	entries, err := parser.parse(data)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := router.Register(entry.pattern, entry.spec, entry.configs...); err != nil {
+			return parser.errorf(entry.node, "%s", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *routeFileParser) parse(data []byte) ([]routeFileEntry, error) {
+	document := &yaml.Node{}
+	if err := yaml.Unmarshal(data, document); err != nil {
+		return nil, fmt.Errorf("%s: %s", p.filename, err)
+	}
+
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	fields, err := p.fields(document.Content[0], "routes")
+	if err != nil {
+		return nil, err
+	}
+
+	routes, ok := fields["routes"]
+	if !ok {
+		return nil, nil
+	}
+
+	if routes.Kind != yaml.SequenceNode {
+		return nil, p.errorf(routes, "expected a list of routes")
+	}
+
+	entries := make([]routeFileEntry, 0, len(routes.Content))
+	for _, node := range routes.Content {
+		entry, err := p.parseRoute(node)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (p *routeFileParser) parseRoute(node *yaml.Node) (routeFileEntry, error) {
+	fields, err := p.fields(node, "pattern", "spec", "name", "middleware", "methods")
+	if err != nil {
+		return routeFileEntry{}, err
+	}
+
+	pattern, err := p.requiredScalar(node, fields, "pattern")
+	if err != nil {
+		return routeFileEntry{}, err
+	}
+
+	specName, err := p.requiredScalar(node, fields, "spec")
+	if err != nil {
+		return routeFileEntry{}, err
+	}
+
+	spec, ok := p.registry.specs[specName]
+	if !ok {
+		return routeFileEntry{}, p.errorf(fields["spec"], "unknown resource spec `%s`", specName)
+	}
+
+	entry := routeFileEntry{
+		node:    node,
+		pattern: pattern,
+		spec:    spec,
+	}
+
+	if nameNode, ok := fields["name"]; ok {
+		name, err := p.scalar(nameNode)
+		if err != nil {
+			return routeFileEntry{}, err
+		}
+
+		entry.configs = append(entry.configs, WithName(name))
+	}
+
+	if middlewareNode, ok := fields["middleware"]; ok {
+		middleware, err := p.parseMiddlewareList(middlewareNode)
+		if err != nil {
+			return routeFileEntry{}, err
+		}
+
+		for _, m := range middleware {
+			entry.configs = append(entry.configs, WithMiddleware(m))
+		}
+	}
+
+	if methodsNode, ok := fields["methods"]; ok {
+		configs, err := p.parseMethods(methodsNode)
+		if err != nil {
+			return routeFileEntry{}, err
+		}
+
+		entry.configs = append(entry.configs, configs...)
+	}
+
+	return entry, nil
+}
+
+func (p *routeFileParser) parseMethods(node *yaml.Node) ([]RouteConfig, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, p.errorf(node, "expected a map from methods to middleware")
+	}
+
+	configs := []RouteConfig{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name, err := p.scalar(node.Content[i])
+		if err != nil {
+			return nil, err
+		}
+
+		method, ok := lookupMethod(strings.ToUpper(name))
+		if !ok {
+			return nil, p.errorf(node.Content[i], "unknown method `%s`", name)
+		}
+
+		middleware, err := p.parseMiddlewareList(node.Content[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range middleware {
+			configs = append(configs, WithMiddlewareFor(m, method))
+		}
+	}
+
+	return configs, nil
+}
+
+func (p *routeFileParser) parseMiddlewareList(node *yaml.Node) ([]Middleware, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, p.errorf(node, "expected a list of middleware")
+	}
+
+	middleware := make([]Middleware, 0, len(node.Content))
+	for _, child := range node.Content {
+		m, err := p.parseMiddleware(child)
+		if err != nil {
+			return nil, err
+		}
+
+		middleware = append(middleware, m)
+	}
+
+	return middleware, nil
+}
+
+// parseMiddleware constructs middleware from either a bare name or a map
+// with a name and optional parameters.
+func (p *routeFileParser) parseMiddleware(node *yaml.Node) (Middleware, error) {
+	var (
+		nameNode   = node
+		paramsNode *yaml.Node
+	)
+
+	if node.Kind == yaml.MappingNode {
+		fields, err := p.fields(node, "name", "params")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := fields["name"]; !ok {
+			return nil, p.errorf(node, "middleware is missing field `name`")
+		}
+
+		nameNode, paramsNode = fields["name"], fields["params"]
+	}
+
+	name, err := p.scalar(nameNode)
+	if err != nil {
+		return nil, err
+	}
+
+	constructor, ok := p.registry.middleware[name]
+	if !ok {
+		return nil, p.errorf(nameNode, "unknown middleware `%s`", name)
+	}
+
+	middleware, err := constructor(MiddlewareParams{node: paramsNode})
+	if err != nil {
+		errorNode := node
+		if paramsNode != nil {
+			errorNode = paramsNode
+		}
+
+		return nil, p.errorf(errorNode, "invalid parameters for middleware `%s` (%s)", name, err)
+	}
+
+	return middleware, nil
+}
+
+// fields returns the values of the given mapping node keyed by name. It is
+// an error for the mapping to contain a key not in the given list.
+func (p *routeFileParser) fields(node *yaml.Node, allowed ...string) (map[string]*yaml.Node, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, p.errorf(node, "expected a map")
+	}
+
+	fields := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+
+		if !containsString(allowed, key) {
+			return nil, p.errorf(node.Content[i], "unknown field `%s`", key)
+		}
+
+		fields[key] = node.Content[i+1]
+	}
+
+	return fields, nil
+}
+
+func (p *routeFileParser) requiredScalar(node *yaml.Node, fields map[string]*yaml.Node, name string) (string, error) {
+	field, ok := fields[name]
+	if !ok {
+		return "", p.errorf(node, "route is missing field `%s`", name)
+	}
+
+	return p.scalar(field)
+}
+
+func (p *routeFileParser) scalar(node *yaml.Node) (string, error) {
+	if node.Kind != yaml.ScalarNode || node.Value == "" {
+		return "", p.errorf(node, "expected a non-empty string")
+	}
+
+	return node.Value, nil
+}
+
+func (p *routeFileParser) errorf(node *yaml.Node, format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s", p.filename, node.Line, fmt.Sprintf(format, args...))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package chevron
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type RouteFileSuite struct{}
+
+const testRouteFile = `
+routes:
+  - pattern: /users
+    spec: users
+    name: users
+    middleware:
+      - name: header
+        params: {value: outer}
+    methods:
+      post:
+        - name: header
+          params: {value: inner}
+  - pattern: /teams
+    spec: users
+`
+
+func (s *RouteFileSuite) TestRegisterRoutes(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(RegisterRoutes(router, newTestRouteRegistry(), "routes.yaml", []byte(testRouteFile))).To(BeNil())
+
+	recorder := serveRequest(router, "GET", "/users", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.HeaderMap["X-Trace"]).To(Equal([]string{"outer"}))
+
+	recorder = serveRequest(router, "POST", "/users", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.HeaderMap["X-Trace"]).To(Equal([]string{"inner", "outer"}))
+
+	recorder = serveRequest(router, "POST", "/teams", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.HeaderMap["X-Trace"]).To(BeEmpty())
+
+	url, err := router.URL("users")
+	Expect(err).To(BeNil())
+	Expect(url.String()).To(Equal("/users"))
+}
+
+func (s *RouteFileSuite) TestRegisterRoutesJSON(t sweet.T) {
+	data := `{"routes": [{"pattern": "/users", "spec": "users", "middleware": ["header"]}]}`
+
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(RegisterRoutes(router, newTestRouteRegistry(), "routes.json", []byte(data))).To(BeNil())
+
+	recorder := serveRequest(router, "GET", "/users", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.HeaderMap["X-Trace"]).To(Equal([]string{"default"}))
+}
+
+func (s *RouteFileSuite) TestRegisterRoutesErrors(t sweet.T) {
+	testCases := []struct {
+		data  string
+		error string
+	}{
+		{"routes:\n  - pattern: /users\n    spec: accounts\n", "routes.yaml:3: unknown resource spec `accounts`"},
+		{"routes:\n  - pattern: /users\n    spec: users\n    middleware: [cache]\n", "routes.yaml:4: unknown middleware `cache`"},
+		{"routes:\n  - pattern: /users\n    spec: users\n    methods:\n      FETCH: [header]\n", "routes.yaml:5: unknown method `FETCH`"},
+		{"routes:\n  - spec: users\n", "routes.yaml:2: route is missing field `pattern`"},
+		{"routes:\n  - pattern: /users\n    spec: users\n    handler: users\n", "routes.yaml:4: unknown field `handler`"},
+		{"routes:\n  - pattern: /users\n    spec: users\n    middleware:\n      - name: header\n        params: {value: [a, b]}\n", "routes.yaml:6: invalid parameters for middleware `header`"},
+		{"routes:\n  - pattern: /users\n    spec: users\n    middleware:\n      - name: header\n        params: {value: \"\"}\n", "routes.yaml:6: invalid parameters for middleware `header` (value must be non-empty)"},
+		{"routes:\n  - pattern: /users\n    spec: users\n  - pattern: /users\n    spec: users\n", "routes.yaml:4: resource already registered to url pattern `/users`"},
+		{"routes: [", "routes.yaml: yaml:"},
+	}
+
+	for _, testCase := range testCases {
+		router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+		err := RegisterRoutes(router, newTestRouteRegistry(), "routes.yaml", []byte(testCase.data))
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(HavePrefix(testCase.error))
+	}
+}
+
+func (s *RouteFileSuite) TestRegisterRoutesNothingRegisteredOnError(t sweet.T) {
+	data := "routes:\n  - pattern: /users\n    spec: users\n  - pattern: /teams\n    spec: teams\n"
+
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(RegisterRoutes(router, newTestRouteRegistry(), "routes.yaml", []byte(data))).NotTo(BeNil())
+	Expect(router.Routes()).To(BeEmpty())
+}
+
+func (s *RouteFileSuite) TestRegistryDuplicateNames(t sweet.T) {
+	registry := newTestRouteRegistry()
+	Expect(registry.RegisterSpec("users", &FullSpec{})).To(MatchError("resource spec already registered with name `users`"))
+	Expect(registry.RegisterMiddleware("header", nil)).To(MatchError("middleware already registered with name `header`"))
+}
+
+func (s *RouteFileSuite) TestRouteFileInitializer(t sweet.T) {
+	dir, err := ioutil.TempDir("", "chevron-routes")
+	Expect(err).To(BeNil())
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "routes.yaml")
+	Expect(ioutil.WriteFile(filename, []byte(testRouteFile), 0644)).To(BeNil())
+
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(NewRouteFileInitializer(filename, newTestRouteRegistry()).Init(nil, router)).To(BeNil())
+	Expect(router.Routes()).To(HaveLen(2))
+
+	err = NewRouteFileInitializer(filepath.Join(dir, "missing.yaml"), newTestRouteRegistry()).Init(nil, router)
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(ContainSubstring("failed to read route file"))
+}
+
+//
+//
+
+func newTestRouteRegistry() *RouteRegistry {
+	registry := NewRouteRegistry()
+	registry.RegisterSpec("users", &FullSpec{})
+	registry.RegisterMiddleware("header", func(params MiddlewareParams) (Middleware, error) {
+		config := struct {
+			Value string `yaml:"value"`
+		}{Value: "default"}
+
+		if err := params.Decode(&config); err != nil {
+			return nil, err
+		}
+
+		if config.Value == "" {
+			return nil, fmt.Errorf("value must be non-empty")
+		}
+
+		return MiddlewareFunc(func(f Handler) (Handler, error) {
+			return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+				resp := f(ctx, req, logger)
+				resp.AddHeader("X-Trace", config.Value)
+				return resp
+			}, nil
+		}), nil
+	})
+
+	return registry
+}
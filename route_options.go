@@ -0,0 +1,191 @@
+package chevron
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type (
+	// RouteConfig configures a resource at the time of its registration. Both
+	// MiddlewareConfigFunc and RouteConfigFunc values conform to this interface
+	// and can be supplied together to Register.
+	RouteConfig interface {
+		applyRoute(*routeOptions)
+	}
+
+	// RouteConfigFunc is a function that configures how a resource is routed.
+	RouteConfigFunc func(*routeOptions)
+
+	routeOptions struct {
+		name           string
+		middleware     []MiddlewareConfigFunc
+		methodHandlers map[Method]Handler
+		host           string
+		schemes        []string
+		headers        []string
+		queries        []string
+		allowOverlap   bool
+		drainExempt    bool
+	}
+)
+
+func (f RouteConfigFunc) applyRoute(o *routeOptions) {
+	f(o)
+}
+
+func (f MiddlewareConfigFunc) applyRoute(o *routeOptions) {
+	o.middleware = append(o.middleware, f)
+}
+
+// WithName sets the name of the route, which can be used to build URLs
+// referencing the resource via the router's URL method.
+func WithName(name string) RouteConfigFunc {
+	return func(o *routeOptions) { o.name = name }
+}
+
+// WithMethodHandler registers a handler for an HTTP method not covered by
+// the ResourceSpec interface (e.g. PROPFIND or QUERY). The handler is
+// decorated by middleware in the same way as the spec's handlers.
+func WithMethodHandler(method string, handler Handler) RouteConfigFunc {
+	return func(o *routeOptions) { o.methodHandlers[RegisterMethod(method)] = handler }
+}
+
+// WithHost restricts the route to requests whose host matches the given
+// template. The template may contain variables (e.g. `{subdomain}.example.com`)
+// which are available as path parameters.
+func WithHost(host string) RouteConfigFunc {
+	return func(o *routeOptions) { o.host = host }
+}
+
+// WithSchemes restricts the route to requests with one of the given URL
+// schemes (e.g. `https`).
+func WithSchemes(schemes ...string) RouteConfigFunc {
+	return func(o *routeOptions) { o.schemes = append(o.schemes, schemes...) }
+}
+
+// WithHeaders restricts the route to requests with the given header values.
+// The pairs are alternating header names and values. An empty value matches
+// any request in which the header is present.
+func WithHeaders(pairs ...string) RouteConfigFunc {
+	return func(o *routeOptions) { o.headers = append(o.headers, pairs...) }
+}
+
+// WithQueries restricts the route to requests with the given query values.
+// The pairs are alternating query parameter names and value templates. The
+// templates may contain variables which are available as path parameters.
+func WithQueries(pairs ...string) RouteConfigFunc {
+	return func(o *routeOptions) { o.queries = append(o.queries, pairs...) }
+}
+
+// WithAllowOverlap permits the route's URL pattern to overlap with or be
+// shadowed by the URL pattern of a previously registered route. When two
+// registered routes match a request, the route registered first is used.
+func WithAllowOverlap() RouteConfigFunc {
+	return func(o *routeOptions) { o.allowOverlap = true }
+}
+
+// withoutDraining exempts the route from in-flight tracking so that it
+// continues to serve requests while the router is draining.
+func withoutDraining() RouteConfigFunc {
+	return func(o *routeOptions) { o.drainExempt = true }
+}
+
+func getRouteOptions(configs []RouteConfig) *routeOptions {
+	options := &routeOptions{
+		methodHandlers: map[Method]Handler{},
+	}
+
+	for _, config := range configs {
+		config.applyRoute(options)
+	}
+
+	return options
+}
+
+// key creates a value which uniquely identifies the set of requests matched
+// by a route with the given URL pattern and the configured matchers.
+func (o *routeOptions) key(pattern string) string {
+	return pattern + o.describeMatchers()
+}
+
+// describeMatchers creates a canonical description of the configured host,
+// scheme, header, and query matchers. Returns an empty string if the route
+// has no such matchers.
+func (o *routeOptions) describeMatchers() string {
+	parts := []string{}
+	if o.host != "" {
+		parts = append(parts, fmt.Sprintf("host `%s`", strings.ToLower(o.host)))
+	}
+
+	if len(o.schemes) > 0 {
+		parts = append(parts, fmt.Sprintf("schemes `%s`", strings.Join(canonicalSchemes(o.schemes), ",")))
+	}
+
+	if len(o.headers) > 0 {
+		parts = append(parts, fmt.Sprintf("headers `%s`", strings.Join(canonicalPairs(o.headers, http.CanonicalHeaderKey), ",")))
+	}
+
+	if len(o.queries) > 0 {
+		parts = append(parts, fmt.Sprintf("queries `%s`", strings.Join(canonicalPairs(o.queries, nil), ",")))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return " with " + strings.Join(parts, ", ")
+}
+
+// matchers creates the canonical form of the configured host, scheme, header,
+// and query matchers.
+func (o *routeOptions) matchers() routeMatchers {
+	return routeMatchers{
+		host:    strings.ToLower(o.host),
+		schemes: canonicalSchemes(o.schemes),
+		headers: canonicalPairs(o.headers, http.CanonicalHeaderKey),
+		queries: canonicalPairs(o.queries, nil),
+	}
+}
+
+// describe creates the base description of a route with the given URL pattern.
+func (o *routeOptions) describe(pattern string) RouteInfo {
+	return RouteInfo{
+		Pattern: pattern,
+		Name:    o.name,
+		Host:    o.host,
+		Schemes: o.schemes,
+		Headers: o.headers,
+		Queries: o.queries,
+	}
+}
+
+func canonicalSchemes(schemes []string) []string {
+	canonical := make([]string, 0, len(schemes))
+	for _, scheme := range schemes {
+		canonical = append(canonical, strings.ToLower(scheme))
+	}
+
+	sort.Strings(canonical)
+	return canonical
+}
+
+func canonicalPairs(pairs []string, canonicalKey func(string) string) []string {
+	canonical := make([]string, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, value := pairs[i], ""
+		if i+1 < len(pairs) {
+			value = pairs[i+1]
+		}
+
+		if canonicalKey != nil {
+			key = canonicalKey(key)
+		}
+
+		canonical = append(canonical, key+"="+value)
+	}
+
+	sort.Strings(canonical)
+	return canonical
+}
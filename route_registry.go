@@ -0,0 +1,72 @@
+package chevron
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// RouteRegistry maps the names used in a route file to resource specs
+	// and middleware constructors.
+	RouteRegistry struct {
+		specs      map[string]ResourceSpec
+		middleware map[string]MiddlewareConstructor
+	}
+
+	// MiddlewareConstructor creates middleware from the parameters supplied
+	// to it in a route file.
+	MiddlewareConstructor func(params MiddlewareParams) (Middleware, error)
+
+	// MiddlewareParams are the parameters supplied to a middleware in a
+	// route file.
+	MiddlewareParams struct {
+		node *yaml.Node
+	}
+)
+
+// NewRouteRegistry creates an empty RouteRegistry.
+func NewRouteRegistry() *RouteRegistry {
+	return &RouteRegistry{
+		specs:      map[string]ResourceSpec{},
+		middleware: map[string]MiddlewareConstructor{},
+	}
+}
+
+// RegisterSpec registers a resource spec under the given name. It is an
+// error to register the same name twice.
+func (r *RouteRegistry) RegisterSpec(name string, spec ResourceSpec) error {
+	if _, ok := r.specs[name]; ok {
+		return fmt.Errorf("resource spec already registered with name `%s`", name)
+	}
+
+	r.specs[name] = spec
+	return nil
+}
+
+// RegisterMiddleware registers a middleware constructor under the given name.
+// It is an error to register the same name twice.
+func (r *RouteRegistry) RegisterMiddleware(name string, constructor MiddlewareConstructor) error {
+	if _, ok := r.middleware[name]; ok {
+		return fmt.Errorf("middleware already registered with name `%s`", name)
+	}
+
+	r.middleware[name] = constructor
+	return nil
+}
+
+// Empty returns true if no parameters were supplied.
+func (p MiddlewareParams) Empty() bool {
+	return p.node == nil
+}
+
+// Decode unmarshals the parameters into the given value, which should be
+// a pointer to a struct with `yaml` tags. Decode does nothing if there are
+// no parameters.
+func (p MiddlewareParams) Decode(v interface{}) error {
+	if p.node == nil {
+		return nil
+	}
+
+	return p.node.Decode(v)
+}
@@ -0,0 +1,103 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"runtime"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+)
+
+type (
+	// RouteInfo describes a resource or handler registered to a router.
+	RouteInfo struct {
+		// Pattern is the URL pattern of the route, including group prefixes.
+		Pattern string `json:"pattern"`
+
+		// Name is the name of the route, if one was supplied.
+		Name string `json:"name,omitempty"`
+
+		// Host is the host template to which the route is restricted.
+		Host string `json:"host,omitempty"`
+
+		// Schemes are the URL schemes to which the route is restricted.
+		Schemes []string `json:"schemes,omitempty"`
+
+		// Headers are the header name and value pairs to which the route
+		// is restricted.
+		Headers []string `json:"headers,omitempty"`
+
+		// Queries are the query name and value template pairs to which the
+		// route is restricted.
+		Queries []string `json:"queries,omitempty"`
+
+		// Version is the API version served by the resource, if the resource
+		// was registered via RegisterVersions.
+		Version string `json:"version,omitempty"`
+
+		// Methods are the HTTP methods implemented by the resource. This
+		// value is empty for handlers registered via RegisterHandler.
+		Methods []string `json:"methods,omitempty"`
+
+		// Middleware is a map from HTTP methods to the names of the middleware
+		// applied to that method's handler, ordered from outermost to innermost.
+		Middleware map[string][]string `json:"middleware,omitempty"`
+	}
+
+	// RouteTableSpec is a ResourceSpec that serves the routes registered to
+	// the router as a JSON array.
+	RouteTableSpec struct {
+		*EmptySpec
+	}
+
+	// middlewareTrace collects the names of the middleware applied to each
+	// method of a handler map during decoration.
+	middlewareTrace map[Method][]string
+)
+
+// NewRouteTableSpec creates a ResourceSpec that serves the routes registered
+// to the router as a JSON array.
+func NewRouteTableSpec() ResourceSpec {
+	return &RouteTableSpec{}
+}
+
+// Get returns the routes of the router registered to the request context.
+func (s *RouteTableSpec) Get(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	router, ok := ctx.Value(TokenRouter).(Router)
+	if !ok {
+		return DefaultResponse(ctx, req, http.StatusInternalServerError)
+	}
+
+	return response.JSON(router.Routes())
+}
+
+// middlewareName returns the name of the given middleware. This is the value
+// returned by the middleware's Name method, if it conforms to NamedMiddleware,
+// or the name of its underlying function or type otherwise.
+func middlewareName(middleware Middleware) string {
+	if named, ok := middleware.(NamedMiddleware); ok {
+		return named.Name()
+	}
+
+	if f, ok := middleware.(MiddlewareFunc); ok {
+		return funcName(f)
+	}
+
+	t := reflect.TypeOf(middleware)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.String()
+}
+
+// funcName returns the fully qualified name of the given function value.
+func funcName(f interface{}) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer()); fn != nil {
+		return fn.Name()
+	}
+
+	return ""
+}
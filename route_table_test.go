@@ -0,0 +1,143 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type RouteTableSuite struct{}
+
+func (s *RouteTableSuite) TestRoutes(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	router.AddMiddleware(&namedMiddleware{name: "a"})
+
+	Expect(router.Register(
+		"/users",
+		&SimpleGetSpec{},
+		WithName("users"),
+		WithMiddleware(&namedMiddleware{name: "b"}),
+		WithMiddlewareFor(&namedMiddleware{name: "c"}, MethodOptions),
+	)).To(BeNil())
+
+	Expect(router.Group("/api", &namedMiddleware{name: "d"}).Register("/posts", &EmptySpec{})).To(BeNil())
+	router.RegisterHandler("/metrics", http.NotFoundHandler())
+
+	Expect(router.Routes()).To(Equal([]RouteInfo{
+		{
+			Pattern: "/users",
+			Name:    "users",
+			Methods: []string{"GET", "HEAD", "OPTIONS"},
+			Middleware: map[string][]string{
+				"GET":     {"a", "b"},
+				"HEAD":    {"a", "b"},
+				"OPTIONS": {"a", "b", "c"},
+			},
+		},
+		{
+			Pattern: "/api/posts",
+			Methods: []string{"OPTIONS"},
+			Middleware: map[string][]string{
+				"OPTIONS": {"d", "a"},
+			},
+		},
+		{
+			Pattern: "/metrics",
+		},
+	}))
+}
+
+func (s *RouteTableSuite) TestRoutesCustomConfig(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	router.AddMiddleware(&namedMiddleware{name: "a"})
+
+	Expect(router.Register(
+		"/users",
+		&SimpleGetSpec{},
+		MiddlewareConfigFunc(wrapGetConfig),
+		WithMiddleware(&namedMiddleware{name: "b"}),
+	)).To(BeNil())
+
+	Expect(router.Routes()[0].Middleware).To(Equal(map[string][]string{
+		"GET":     {"a", "wrapGet", "b"},
+		"HEAD":    {"a", "wrapGet", "b"},
+		"OPTIONS": {"a", "b"},
+	}))
+}
+
+func (s *RouteTableSuite) TestMiddlewareName(t sweet.T) {
+	Expect(middlewareName(&namedMiddleware{name: "foo"})).To(Equal("foo"))
+	Expect(middlewareName(&unnamedMiddleware{})).To(Equal("chevron.unnamedMiddleware"))
+	Expect(middlewareName(MiddlewareFunc(identityMiddleware))).To(Equal("github.com/go-nacelle/chevron.identityMiddleware"))
+}
+
+func (s *RouteTableSuite) TestRouteTableSpec(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	Expect(router.Register("/routes", NewRouteTableSpec(), WithName("routes"))).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/routes", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(MatchJSON(`[
+		{
+			"pattern": "/routes",
+			"name": "routes",
+			"methods": ["GET", "HEAD", "OPTIONS"]
+		}
+	]`))
+}
+
+//
+//
+
+type namedMiddleware struct {
+	name string
+}
+
+func (m *namedMiddleware) Name() string {
+	return m.name
+}
+
+func (m *namedMiddleware) Convert(h Handler) (Handler, error) {
+	return h, nil
+}
+
+type unnamedMiddleware struct{}
+
+func (m *unnamedMiddleware) Convert(h Handler) (Handler, error) {
+	return h, nil
+}
+
+func identityMiddleware(h Handler) (Handler, error) {
+	return h, nil
+}
+
+func wrapGetConfig(d *decoration) error {
+	handler := d.hm[MethodGet]
+	d.wrap(MethodGet, "wrapGet", func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return handler(ctx, req, logger)
+	})
+
+	return nil
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
 
 	"github.com/efritz/response"
 	"github.com/go-nacelle/nacelle"
@@ -19,27 +22,96 @@ type (
 		// the invocation of this method.
 		AddMiddleware(middleware Middleware)
 
+		// Use registers middleware that wraps the entire dispatch pipeline of
+		// the router, regardless of the order of registration.
+		Use(middleware Middleware) error
+
 		// Register creates a resource from the given resource spec and set of
 		// middleware instances and registers it to the given URL pattern.
-		Register(url string, spec ResourceSpec, configs ...MiddlewareConfigFunc) error
+		Register(url string, spec ResourceSpec, configs ...RouteConfig) error
+
+		// RegisterVersions creates a resource from each of the given resource
+		// specs, keyed by API version, and registers them to the given URL
+		// pattern. The router must be configured with WithVersioning.
+		RegisterVersions(url string, specs map[string]ResourceSpec, configs ...RouteConfig) error
 
 		// MustRegister calls Register and panics on error.
-		MustRegister(url string, spec ResourceSpec, configs ...MiddlewareConfigFunc)
+		MustRegister(url string, spec ResourceSpec, configs ...RouteConfig)
+
+		// RegisterHandler registers the given handler to the given URL pattern.
+		// If any middleware configs are supplied, the handler is converted into
+		// a chevron handler so that it can be decorated.
+		RegisterHandler(url string, handler http.Handler, configs ...RouteConfig) error
+
+		// Group creates a router whose registered resources share the given URL
+		// prefix and are decorated with the given middleware.
+		Group(prefix string, middleware ...Middleware) Router
+
+		// URL builds a URL for the route registered with the given name. The
+		// parameters are alternating variable names and values.
+		URL(name string, params ...string) (*url.URL, error)
 
-		RegisterHandler(url string, handler http.Handler)
+		// Routes returns a description of each resource and handler registered
+		// to the router in the order of registration.
+		Routes() []RouteInfo
+
+		// SetReady marks the router as ready or no longer ready to serve
+		// traffic, which is reported by the readiness endpoint registered
+		// via WithHealthEndpoints.
+		SetReady(ready bool)
+
+		// Drain marks the router as no longer ready, waits for the drain delay,
+		// then rejects subsequent requests and blocks until the requests in
+		// flight complete or the given context is canceled.
+		Drain(ctx context.Context) error
+
+		// InFlight returns the number of requests currently being served by
+		// the router, overall and by URL pattern.
+		InFlight() InFlightRequests
 	}
 
 	router struct {
 		services              nacelle.ServiceContainer
 		logger                nacelle.Logger
+		root                  *router
+		prefix                string
 		middleware            []Middleware
+		groupMiddleware       []Middleware
+		pipelineMiddleware    []Middleware
+		pipeline              http.Handler
 		mux                   *mux.Router
 		resources             map[string]struct{}
+		routes                []RouteInfo
+		patterns              []registeredPattern
 		notFoundHandler       Handler
 		notImplementedHandler Handler
+		badRequestHandler     BadRequestHandler
+		errorMapper           ErrorMapper
+		problemOptions        *problemOptions
+		trailingSlashPolicy   PathPolicy
+		cleanPathPolicy       PathPolicy
+		caseInsensitivePolicy PathPolicy
+		versionOptions        *versionOptions
+		healthOptions         *healthOptions
+		lifecycle             int32
+		inFlight              *inFlightTracker
+		drainRetryAfter       time.Duration
+		drainDelay            time.Duration
+		drainHandler          http.Handler
 		baseCtx               context.Context
 	}
 
+	// pendingRoute is a route which has been validated and whose resources
+	// have been decorated, but which is not yet attached to the router.
+	pendingRoute struct {
+		url       string
+		key       string
+		options   *routeOptions
+		candidate registeredPattern
+		handler   Handler
+		routes    []RouteInfo
+	}
+
 	handlerMap map[Method]Handler
 )
 
@@ -52,49 +124,275 @@ func NewRouter(services nacelle.ServiceContainer, logger nacelle.Logger, configs
 		resources:             map[string]struct{}{},
 		notFoundHandler:       defaultNotFoundHandler,
 		notImplementedHandler: defaultNotImplementedHandler,
+		badRequestHandler:     defaultBadRequestHandler,
+		errorMapper:           DefaultErrorMapper,
+		trailingSlashPolicy:   PathPolicyStrict,
+		cleanPathPolicy:       PathPolicyRedirect,
+		caseInsensitivePolicy: PathPolicyStrict,
+		inFlight:              newInFlightTracker(),
+		drainRetryAfter:       time.Second * 5,
 	}
 
+	r.root = r
+
 	for _, config := range configs {
 		config(r)
 	}
 
 	r.baseCtx = setNotImplementedHandler(context.Background(), r.notImplementedHandler)
+	r.baseCtx = setBadRequestHandler(r.baseCtx, r.badRequestHandler)
+	r.baseCtx = setErrorMapper(r.baseCtx, r.errorMapper)
+	r.baseCtx = setRouter(r.baseCtx, r)
+
+	if r.problemOptions != nil {
+		r.baseCtx = setProblemOptions(r.baseCtx, r.problemOptions)
+	}
+
 	r.mux.NotFoundHandler = convert(r.baseCtx, r.notFoundHandler, r.logger)
+	r.mux.SkipClean(true)
+	r.drainHandler = convert(r.baseCtx, makeDrainHandler(r.drainRetryAfter), r.logger)
+
+	if r.healthOptions != nil {
+		r.MustRegister(r.healthOptions.livenessPath, &livenessSpec{}, withoutDraining())
+		r.MustRegister(r.healthOptions.readinessPath, &readinessSpec{router: r, timeout: r.healthOptions.timeout}, withoutDraining())
+	}
+
 	return r
 }
 
 // AddMiddleware registers middleware for all resources. This middleware is not
 // retroactively applied to resources which have already been registered (so
 // attention to the order in which middleware is registered is required on the
-// part of the developer).
+// part of the developer). Middleware added to a group applies only to the
+// resources registered to that group (and its nested groups created after the
+// invocation of this method).
 func (r *router) AddMiddleware(middleware Middleware) {
+	if r.root != r {
+		r.groupMiddleware = append(r.groupMiddleware, middleware)
+		return
+	}
+
 	r.middleware = append(r.middleware, middleware)
 }
 
+// Use registers middleware that wraps the entire dispatch pipeline of the
+// router. Unlike middleware registered via AddMiddleware, this middleware
+// applies to every request regardless of the order of registration, and
+// wraps the not found and not implemented handlers as well as handlers
+// registered via RegisterHandler. The output of such handlers is buffered
+// so that it can be exposed to the middleware as a response object.
+// Middleware registered first is invoked first. Invoking this method on a
+// group registers the middleware to the router owning the group.
+func (r *router) Use(middleware Middleware) error {
+	root := r.root
+	pipelineMiddleware := append(append([]Middleware{}, root.pipelineMiddleware...), middleware)
+
+	handler := Handler(root.dispatch)
+	for i := len(pipelineMiddleware) - 1; i >= 0; i-- {
+		wrapped, err := pipelineMiddleware[i].Convert(handler)
+		if err != nil {
+			return err
+		}
+
+		handler = wrapped
+	}
+
+	root.pipelineMiddleware = pipelineMiddleware
+	root.pipeline = convert(root.baseCtx, handler, root.logger)
+	return nil
+}
+
+// Group creates a router whose registered resources share the given URL
+// prefix and are decorated with the given middleware. Groups can be nested,
+// in which case the prefixes are concatenated. Group middleware is invoked
+// before the router's global middleware, with the middleware of outer groups
+// invoked before the middleware of inner groups.
+func (r *router) Group(prefix string, middleware ...Middleware) Router {
+	groupMiddleware := make([]Middleware, 0, len(r.groupMiddleware)+len(middleware))
+	groupMiddleware = append(groupMiddleware, r.groupMiddleware...)
+	groupMiddleware = append(groupMiddleware, middleware...)
+
+	group := *r
+	group.prefix = r.prefix + prefix
+	group.groupMiddleware = groupMiddleware
+	group.mux = r.mux.PathPrefix(prefix).Subrouter()
+	return &group
+}
+
 // Register creates a resource from the given resource spec and set of
 // middleware instances and registers it to the given URL pattern. It
-// is an error to register the same URL pattern twice.
-func (r *router) Register(url string, spec ResourceSpec, configs ...MiddlewareConfigFunc) error {
-	if _, ok := r.resources[url]; ok {
-		return fmt.Errorf("resource already registered to url pattern `%s`", url)
+// is an error to register the same route name twice, or to register the
+// same URL pattern twice with the same host, scheme, header, and query
+// matchers. Unless WithAllowOverlap is supplied, it is also an error to
+// register a URL pattern which is ambiguous with or shadowed by the URL
+// pattern of a previously registered route whose matchers are no more
+// restrictive.
+func (r *router) Register(url string, spec ResourceSpec, configs ...RouteConfig) error {
+	return r.register(url, map[string]ResourceSpec{"": spec}, false, getRouteOptions(configs))
+}
+
+// register creates a resource from each of the given resource specs, keyed by
+// API version, and registers them to the given URL pattern. If selectVersion
+// is true, the resource serving a request is chosen by the router's versioning
+// options. Otherwise, there must be exactly one spec, and its version (if
+// non-empty) is written to the context of every request.
+func (r *router) register(url string, specs map[string]ResourceSpec, selectVersion bool, options *routeOptions) error {
+	route, err := r.prepare(url, specs, selectVersion, options)
+	if err != nil {
+		return err
 	}
 
-	r.resources[url] = struct{}{}
+	return r.attach(route)
+}
+
+// prepare validates the route described by the arguments of register and
+// decorates its resources without modifying the router.
+func (r *router) prepare(url string, specs map[string]ResourceSpec, selectVersion bool, options *routeOptions) (*pendingRoute, error) {
+	var (
+		pattern = r.prefix + url
+		key     = options.key(pattern)
+	)
 
-	if err := r.services.Inject(spec); err != nil {
-		return err
+	if _, ok := r.resources[key]; ok {
+		return nil, fmt.Errorf("resource already registered to url pattern `%s`%s", pattern, options.describeMatchers())
 	}
 
-	resource, err := r.decorateResource(spec, configs...)
+	if options.name != "" && r.mux.Get(options.name) != nil {
+		return nil, fmt.Errorf("resource already registered with name `%s`", options.name)
+	}
+
+	candidate, err := r.checkPattern(pattern, options)
 	if err != nil {
+		return nil, err
+	}
+
+	if err := validateMatchers(url, options); err != nil {
+		return nil, err
+	}
+
+	var (
+		versions = sortedVersions(specs)
+		handlers = make(map[string]Handler, len(versions))
+		routes   = make([]RouteInfo, 0, len(versions))
+	)
+
+	for _, version := range versions {
+		spec := specs[version]
+
+		if err := r.services.Inject(spec); err != nil {
+			return nil, err
+		}
+
+		resource, err := r.decorateResource(spec, options.methodHandlers, options.middleware...)
+		if err != nil {
+			return nil, err
+		}
+
+		info := resource.describe(options.describe(pattern))
+		info.Version = version
+		routes = append(routes, info)
+		handlers[version] = withAPIVersion(version, resource.Handle)
+	}
+
+	handler := handlers[versions[0]]
+	if selectVersion {
+		handler = r.root.versionOptions.dispatch(handlers, r.notFoundHandler)
+	}
+
+	return &pendingRoute{
+		url:       url,
+		key:       key,
+		options:   options,
+		candidate: candidate,
+		handler:   handler,
+		routes:    routes,
+	}, nil
+}
+
+// attach registers a route created by prepare to the mux.
+func (r *router) attach(route *pendingRoute) error {
+	if err := r.addRoute(route.url, route.options, convert(r.baseCtx, route.handler, r.logger)); err != nil {
 		return err
 	}
 
-	r.RegisterHandler(url, convert(r.baseCtx, resource.Handle, r.logger))
+	r.resources[route.key] = struct{}{}
+	r.root.patterns = append(r.root.patterns, route.candidate)
+	r.root.routes = append(r.root.routes, route.routes...)
 	return nil
 }
 
-func (r *router) decorateResource(spec ResourceSpec, configs ...MiddlewareConfigFunc) (Resource, error) {
+// checkPattern returns an error if the given URL pattern is ambiguous with
+// or shadowed by a previously registered URL pattern, unless the given route
+// options permit overlap.
+func (r *router) checkPattern(pattern string, options *routeOptions) (registeredPattern, error) {
+	candidate := registeredPattern{
+		pattern:  pattern,
+		matchers: options.matchers(),
+		segments: parsePattern(pattern),
+	}
+
+	if r.root.caseInsensitivePolicy != PathPolicyStrict {
+		candidate.fold = compileFoldPattern(pattern)
+	}
+
+	if options.allowOverlap {
+		return candidate, nil
+	}
+
+	return candidate, checkOverlap(r.root.patterns, candidate)
+}
+
+// addRoute registers the given handler to the mux with the given URL pattern
+// and the matchers and name of the given route options.
+func (r *router) addRoute(url string, options *routeOptions, handler http.Handler) error {
+	if err := validateMatchers(url, options); err != nil {
+		return err
+	}
+
+	if !options.drainExempt {
+		handler = r.trackInFlight(r.prefix+url, handler)
+	}
+
+	route := applyMatchers(r.mux.NewRoute(), url, options).Handler(handler)
+
+	if options.name != "" {
+		route.Name(options.name)
+	}
+
+	return route.GetError()
+}
+
+// validateMatchers returns an error if the given URL pattern or the matchers
+// of the given options are invalid. The matchers are applied to a detached
+// route so that an invalid route is never attached to the mux or registered
+// under its name.
+func validateMatchers(url string, options *routeOptions) error {
+	return applyMatchers(mux.NewRouter().NewRoute(), url, options).GetError()
+}
+
+// applyMatchers restricts the given route to the given URL pattern and the
+// host, schemes, headers, and queries of the given options.
+func applyMatchers(route *mux.Route, url string, options *routeOptions) *mux.Route {
+	if options.host != "" {
+		route.Host(options.host)
+	}
+
+	if len(options.schemes) > 0 {
+		route.Schemes(options.schemes...)
+	}
+
+	if len(options.headers) > 0 {
+		route.Headers(options.headers...)
+	}
+
+	if len(options.queries) > 0 {
+		route.Queries(options.queries...)
+	}
+
+	return route.Path(url)
+}
+
+func (r *router) decorateResource(spec ResourceSpec, methodHandlers map[Method]Handler, configs ...MiddlewareConfigFunc) (*resource, error) {
 	hm := handlerMap{
 		MethodGet:     spec.Get,
 		MethodOptions: spec.Options,
@@ -104,53 +402,197 @@ func (r *router) decorateResource(spec ResourceSpec, configs ...MiddlewareConfig
 		MethodDelete:  spec.Delete,
 	}
 
+	methods := implementedMethods(spec)
+	if head, ok := spec.(headSpec); ok {
+		methods = insertMethod(methods, MethodHead)
+		hm[MethodHead] = head.Head
+	}
+
+	for method, handler := range methodHandlers {
+		methods = insertMethod(methods, method)
+		hm[method] = handler
+	}
+
+	// A HEAD handler is derived from the GET handler unless one was supplied
+	_, explicitHead := hm[MethodHead]
+	if containsMethod(methods, MethodGet) {
+		methods = insertMethod(methods, MethodHead)
+	}
+
+	if !containsMethod(methods, MethodOptions) {
+		methods = insertMethod(methods, MethodOptions)
+		hm[MethodOptions] = makeOptionsHandler(makeAllowHeader(methods))
+	}
+
+	d := newDecoration(hm)
 	for i := len(configs) - 1; i >= 0; i-- {
-		if err := configs[i](hm); err != nil {
+		if err := configs[i](d); err != nil {
 			return nil, err
 		}
 	}
 
-	for i := len(r.middleware) - 1; i >= 0; i-- {
-		if err := applyMiddleware(r.middleware[i], hm, allMethods); err != nil {
+	for i := len(r.root.middleware) - 1; i >= 0; i-- {
+		if err := applyMiddleware(r.root.middleware[i], d, hm.methods()); err != nil {
 			return nil, err
 		}
 	}
 
-	return &resource{hm: hm, router: r}, nil
+	for i := len(r.groupMiddleware) - 1; i >= 0; i-- {
+		if err := applyMiddleware(r.groupMiddleware[i], d, hm.methods()); err != nil {
+			return nil, err
+		}
+	}
+
+	if !explicitHead {
+		hm[MethodHead] = makeHeadHandler(hm[MethodGet])
+		d.trace[MethodHead] = d.trace[MethodGet]
+	}
+
+	return &resource{
+		hm:         hm,
+		router:     r,
+		methods:    methods,
+		allow:      makeAllowHeader(methods),
+		middleware: d.trace,
+	}, nil
 }
 
 // MustRegister calls Register and panics on error.
-func (r *router) MustRegister(url string, spec ResourceSpec, configs ...MiddlewareConfigFunc) {
+func (r *router) MustRegister(url string, spec ResourceSpec, configs ...RouteConfig) {
 	if err := r.Register(url, spec, configs...); err != nil {
 		panic(err.Error())
 	}
 }
 
-func (r *router) RegisterHandler(url string, handler http.Handler) {
-	r.mux.Handle(url, handler)
+// RegisterHandler registers the given handler to the given URL pattern. If
+// any middleware configs are supplied, the handler is wrapped by WrapHandler
+// and decorated for every HTTP method known to the router. Otherwise, the
+// handler is registered as-is and its output is not buffered. Unlike Register,
+// the router's global and group middleware is not applied to the handler.
+func (r *router) RegisterHandler(url string, handler http.Handler, configs ...RouteConfig) error {
+	var (
+		options = getRouteOptions(configs)
+		info    = options.describe(r.prefix + url)
+	)
+
+	if options.name != "" && r.mux.Get(options.name) != nil {
+		return fmt.Errorf("resource already registered with name `%s`", options.name)
+	}
+
+	candidate, err := r.checkPattern(info.Pattern, options)
+	if err != nil {
+		return err
+	}
+
+	if len(options.middleware) > 0 {
+		resource, err := r.decorateHandler(WrapHandler(handler), options.middleware...)
+		if err != nil {
+			return err
+		}
+
+		handler = convert(r.baseCtx, resource.Handle, r.logger)
+		info.Middleware = resource.describe(info).Middleware
+	}
+
+	if err := r.addRoute(url, options, handler); err != nil {
+		return err
+	}
+
+	r.root.patterns = append(r.root.patterns, candidate)
+	r.root.routes = append(r.root.routes, info)
+	return nil
+}
+
+func (r *router) decorateHandler(handler Handler, configs ...MiddlewareConfigFunc) (*resource, error) {
+	hm := handlerMap{}
+	for _, method := range registeredMethods() {
+		hm[method] = handler
+	}
+
+	d := newDecoration(hm)
+	for i := len(configs) - 1; i >= 0; i-- {
+		if err := configs[i](d); err != nil {
+			return nil, err
+		}
+	}
+
+	return &resource{
+		hm:         hm,
+		router:     r,
+		methods:    hm.methods(),
+		middleware: d.trace,
+	}, nil
+}
+
+// URL builds a URL for the route registered with the given name. The
+// parameters are alternating variable names and values. It is an error
+// to omit a variable declared by the route's URL pattern or to supply a
+// value which does not match the variable's pattern.
+func (r *router) URL(name string, params ...string) (*url.URL, error) {
+	route := r.mux.Get(name)
+	if route == nil {
+		return nil, fmt.Errorf("no resource registered with name `%s`", name)
+	}
+
+	return route.URL(params...)
+}
+
+// Routes returns a description of each resource and handler registered
+// to the router (including those registered to its groups) in the order
+// of registration.
+func (r *router) Routes() []RouteInfo {
+	routes := make([]RouteInfo, len(r.root.routes))
+	copy(routes, r.root.routes)
+	return routes
+}
+
+// SetReady marks the router as ready or no longer ready to serve traffic.
+// A router which is no longer ready after having been ready is considered
+// to be shutting down.
+func (r *router) SetReady(ready bool) {
+	if ready {
+		atomic.StoreInt32(&r.root.lifecycle, lifecycleReady)
+		return
+	}
+
+	atomic.CompareAndSwapInt32(&r.root.lifecycle, lifecycleReady, lifecycleStopping)
 }
 
 // ServeHTTP invokes the handler registered to the request URL and
 // writes the response to the given ResponseWriter.
 func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	r.mux.ServeHTTP(w, req)
-}
+	// Make problem details options available to helpers (such as Negotiate)
+	// which are invoked with only the request
+	if options := r.root.problemOptions; options != nil {
+		req = req.WithContext(setProblemOptions(req.Context(), options))
+	}
 
-//
-//
+	if r.root.pipeline != nil {
+		r.root.pipeline.ServeHTTP(w, req)
+		return
+	}
 
-func convert(ctx context.Context, handler Handler, logger nacelle.Logger) http.Handler {
-	responseHandler := func(r *http.Request) response.Response {
-		return handler(ctx, r, logger)
+	r.serveMux(w, req)
+}
+
+// methods returns the HTTP methods with a registered handler.
+func (hm handlerMap) methods() []Method {
+	methods := make([]Method, 0, len(hm))
+	for method := range hm {
+		methods = append(methods, method)
 	}
 
-	return http.HandlerFunc(response.Convert(responseHandler))
+	sortMethods(methods)
+	return methods
 }
 
+//
+//
+
 func defaultNotFoundHandler(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
-	return response.Empty(http.StatusNotFound)
+	return DefaultResponse(ctx, r, http.StatusNotFound)
 }
 
 func defaultNotImplementedHandler(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
-	return response.Empty(http.StatusMethodNotAllowed)
+	return DefaultResponse(ctx, r, http.StatusMethodNotAllowed)
 }
@@ -1,6 +1,8 @@
 package chevron
 
 import (
+	"time"
+
 	"github.com/go-nacelle/nacelle"
 )
 
@@ -23,3 +25,103 @@ func WithNotFoundHandler(handler Handler) RouterConfigFunc {
 func WithNotImplementedHandler(handler Handler) RouterConfigFunc {
 	return func(r *router) { r.notImplementedHandler = handler }
 }
+
+// WithBadRequestHandler sets the handler invoked when a request
+// cannot be processed due to malformed input, such as a path
+// parameter which cannot be converted to the requested type.
+func WithBadRequestHandler(handler BadRequestHandler) RouterConfigFunc {
+	return func(r *router) { r.badRequestHandler = handler }
+}
+
+// WithErrorMapper sets the function that converts errors returned
+// from a HandlerE into a response. By default, DefaultErrorMapper
+// is used.
+func WithErrorMapper(mapper ErrorMapper) RouterConfigFunc {
+	return func(r *router) { r.errorMapper = mapper }
+}
+
+// WithProblemDetails causes the router to respond with RFC 7807 problem
+// details (application/problem+json) in place of the bodiless responses
+// created by default, including the responses of the router's default
+// handlers, of the default error mapper, and of the default response
+// factories of the middleware package. Responses created by user handlers
+// are converted only when WithProblemConversion is also supplied.
+func WithProblemDetails(configs ...ProblemConfigFunc) RouterConfigFunc {
+	return func(r *router) {
+		r.problemOptions = &problemOptions{extensions: map[string]ProblemExtensionFunc{}}
+
+		for _, f := range configs {
+			f(r.problemOptions)
+		}
+	}
+}
+
+// WithTrailingSlashPolicy sets how the router treats a request path
+// that matches a registered URL pattern only once a trailing slash is
+// added or removed. By default, such paths are not matched.
+func WithTrailingSlashPolicy(policy PathPolicy) RouterConfigFunc {
+	return func(r *router) { r.trailingSlashPolicy = policy }
+}
+
+// WithCleanPathPolicy sets how the router treats a request path that
+// contains duplicate slashes or dot segments. By default, such paths
+// are redirected to their cleaned form.
+func WithCleanPathPolicy(policy PathPolicy) RouterConfigFunc {
+	return func(r *router) { r.cleanPathPolicy = policy }
+}
+
+// WithCaseInsensitivePolicy sets how the router treats a request path
+// that matches the literal text of a registered URL pattern only when
+// compared without regard to case. By default, such paths are not
+// matched. The values of path parameters retain their original case.
+func WithCaseInsensitivePolicy(policy PathPolicy) RouterConfigFunc {
+	return func(r *router) { r.caseInsensitivePolicy = policy }
+}
+
+// WithVersioning enables the registration of resources with multiple API
+// versions via RegisterVersions. The configs determine how the version of
+// a request is selected.
+func WithVersioning(configs ...VersionConfigFunc) RouterConfigFunc {
+	return func(r *router) {
+		r.versionOptions = &versionOptions{}
+
+		for _, f := range configs {
+			f(r.versionOptions)
+		}
+	}
+}
+
+// WithHealthEndpoints registers a liveness and a readiness resource to the
+// router. The liveness endpoint always reports success. The readiness endpoint
+// reports failure until the router is marked ready (see Router.SetReady), once
+// shutdown begins, and while any of the health checks registered to the service
+// container (see GetHealthChecks) fail. The health endpoints are registered
+// before any middleware added to the router.
+func WithHealthEndpoints(configs ...HealthConfigFunc) RouterConfigFunc {
+	return func(r *router) {
+		r.healthOptions = &healthOptions{
+			livenessPath:  "/livez",
+			readinessPath: "/readyz",
+			timeout:       time.Second,
+		}
+
+		for _, f := range configs {
+			f(r.healthOptions)
+		}
+	}
+}
+
+// WithDrainRetryAfter sets the value of the Retry-After header of the 503-level
+// response returned for requests received while the router is draining. The
+// duration is rounded up to the nearest second. The default is five seconds.
+func WithDrainRetryAfter(retryAfter time.Duration) RouterConfigFunc {
+	return func(r *router) { r.drainRetryAfter = retryAfter }
+}
+
+// WithDrainDelay sets the duration for which a draining router continues to
+// serve requests after it is marked as no longer ready. This gives a load
+// balancer polling the readiness endpoint time to stop routing traffic to
+// the router before it begins to reject requests. The default is zero.
+func WithDrainDelay(delay time.Duration) RouterConfigFunc {
+	return func(r *router) { r.drainDelay = delay }
+}
@@ -2,12 +2,14 @@ package chevron
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 
 	"github.com/aphistic/sweet"
 	"github.com/efritz/response"
 	"github.com/go-nacelle/nacelle"
+	"github.com/gorilla/mux"
 	. "github.com/onsi/gomega"
 )
 
@@ -110,6 +112,639 @@ func (s *RouterSuite) TestRegisterWithMiddleware(t sweet.T) {
 	Expect(calls).To(Equal([]string{"a", "b", "c", "d", "e", "f"}))
 }
 
+func (s *RouterSuite) TestGroup(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+		api       = router.Group("/api")
+		v1        = api.Group("/v1")
+	)
+
+	Expect(router.Register("/foo", &SimpleGetSpec{})).To(BeNil())
+	Expect(v1.Register("/foo", &SimpleGetSpec{})).To(BeNil())
+
+	for _, url := range []string{"/foo", "/api/v1/foo"} {
+		req, _ := http.NewRequest("GET", url, nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		Expect(recorder.Code).To(Equal(http.StatusNoContent))
+	}
+
+	for _, url := range []string{"/api/foo", "/api/v1/bar", "/v1/foo"} {
+		req, _ := http.NewRequest("GET", url, nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	}
+
+	// Duplicate detection considers the group prefix
+	err := api.Register("/v1/foo", &SimpleGetSpec{})
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(ContainSubstring("resource already registered to url pattern `/api/v1/foo`"))
+}
+
+func (s *RouterSuite) TestGroupWithMiddleware(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+		calls     = []string{}
+	)
+
+	middlewareFactory := func(name string) Middleware {
+		return MiddlewareFunc(func(h Handler) (Handler, error) {
+			handler := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+				calls = append(calls, name)
+				return h(ctx, r, logger)
+			}
+
+			return handler, nil
+		})
+	}
+
+	outer := router.Group("/outer", middlewareFactory("a"), middlewareFactory("b"))
+	inner := outer.Group("/inner", middlewareFactory("c"))
+
+	// Global middleware applies to groups created before its registration
+	router.AddMiddleware(middlewareFactory("d"))
+
+	err := inner.Register(
+		"/foo",
+		&SimpleGetSpec{},
+		WithMiddleware(middlewareFactory("e")),
+	)
+
+	Expect(err).To(BeNil())
+	Expect(outer.Register("/foo", &SimpleGetSpec{})).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/outer/inner/foo", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusNoContent))
+	Expect(calls).To(Equal([]string{"a", "b", "c", "d", "e"}))
+
+	calls = calls[:0]
+	req, _ = http.NewRequest("GET", "/outer/foo", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusNoContent))
+	Expect(calls).To(Equal([]string{"a", "b", "d"}))
+}
+
+func (s *RouterSuite) TestURL(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	Expect(router.Register("/users/{id:[0-9]+}", &SimpleGetSpec{}, WithName("user"))).To(BeNil())
+	Expect(router.Group("/api").Register("/posts/{slug}", &SimpleGetSpec{}, WithName("post"))).To(BeNil())
+
+	url, err := router.URL("user", "id", "123")
+	Expect(err).To(BeNil())
+	Expect(url.String()).To(Equal("/users/123"))
+
+	url, err = router.URL("post", "slug", "hello")
+	Expect(err).To(BeNil())
+	Expect(url.String()).To(Equal("/api/posts/hello"))
+
+	_, err = router.URL("user")
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(ContainSubstring("missing route variable"))
+
+	_, err = router.URL("user", "id", "foo")
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(ContainSubstring("doesn't match"))
+
+	_, err = router.URL("unknown")
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(ContainSubstring("no resource registered with name `unknown`"))
+}
+
+func (s *RouterSuite) TestURLFromContext(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	spec := &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		url, err := GetURL(ctx, "user", "id", "123")
+		if err != nil {
+			return response.Empty(http.StatusInternalServerError)
+		}
+
+		return response.Empty(http.StatusCreated).SetHeader("Location", url.String())
+	}}
+
+	Expect(router.Register("/users", spec)).To(BeNil())
+	Expect(router.Register("/users/{id}", &SimpleGetSpec{}, WithName("user"))).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusCreated))
+	Expect(recorder.HeaderMap.Get("Location")).To(Equal("/users/123"))
+
+	_, err := GetURL(context.Background(), "user")
+	Expect(err).NotTo(BeNil())
+}
+
+func (s *RouterSuite) TestRegisterDuplicateName(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	err1 := router.Register("/users", &EmptySpec{}, WithName("users"))
+	err2 := router.Group("/api").Register("/users", &EmptySpec{}, WithName("users"))
+
+	Expect(err1).To(BeNil())
+	Expect(err2).NotTo(BeNil())
+	Expect(err2.Error()).To(ContainSubstring("resource already registered with name `users`"))
+}
+
+func (s *RouterSuite) TestNotImplementedSetsAllowHeader(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	Expect(router.Register("/foo", &SimpleGetSpec{})).To(BeNil())
+
+	req, _ := http.NewRequest("DELETE", "/foo", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+	Expect(recorder.HeaderMap.Get("Allow")).To(Equal("GET, HEAD, OPTIONS"))
+}
+
+func (s *RouterSuite) TestAutomaticOptions(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	Expect(router.Register("/foo", &SimpleGetSpec{})).To(BeNil())
+	Expect(router.Register("/bar", &FullSpec{})).To(BeNil())
+
+	req, _ := http.NewRequest("OPTIONS", "/foo", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusNoContent))
+	Expect(recorder.HeaderMap.Get("Allow")).To(Equal("GET, HEAD, OPTIONS"))
+
+	// Overridden options handler is not replaced
+	req, _ = http.NewRequest("OPTIONS", "/bar", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.HeaderMap.Get("Allow")).To(BeEmpty())
+}
+
+func (s *RouterSuite) TestHead(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+		calls     = 0
+	)
+
+	middleware := MiddlewareFunc(func(h Handler) (Handler, error) {
+		handler := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+			calls++
+			return h(ctx, r, logger)
+		}
+
+		return handler, nil
+	})
+
+	Expect(router.Register("/foo", &TestSpec{}, WithMiddlewareFor(middleware, MethodGet))).To(BeNil())
+	Expect(router.Register("/bar", &EmptySpec{})).To(BeNil())
+
+	req, _ := http.NewRequest("HEAD", "/foo", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal("application/json"))
+	Expect(recorder.HeaderMap.Get("Content-Length")).To(Equal("19"))
+	Expect(recorder.Body.Len()).To(Equal(0))
+	Expect(calls).To(Equal(1))
+
+	// Resources without GET do not support HEAD
+	req, _ = http.NewRequest("HEAD", "/bar", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+	Expect(recorder.HeaderMap.Get("Allow")).To(Equal("OPTIONS"))
+}
+
+func (s *RouterSuite) TestHeadExplicit(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	head := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+		return response.Empty(http.StatusOK).SetHeader("X-Head", "handler")
+	}
+
+	Expect(router.Register("/foo", &TestSpec{}, WithMethodHandler("HEAD", head))).To(BeNil())
+	Expect(router.Register("/bar", &HeadSpec{})).To(BeNil())
+
+	req, _ := http.NewRequest("HEAD", "/foo", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.HeaderMap.Get("X-Head")).To(Equal("handler"))
+
+	req, _ = http.NewRequest("HEAD", "/bar", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.HeaderMap.Get("X-Head")).To(Equal("spec"))
+	Expect(router.Routes()[1].Methods).To(Equal([]string{"GET", "HEAD", "OPTIONS"}))
+}
+
+func (s *RouterSuite) TestWithMethodHandler(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+		calls     = []string{}
+	)
+
+	middlewareFactory := func(name string) Middleware {
+		return MiddlewareFunc(func(h Handler) (Handler, error) {
+			handler := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+				calls = append(calls, name)
+				return h(ctx, r, logger)
+			}
+
+			return handler, nil
+		})
+	}
+
+	router.AddMiddleware(middlewareFactory("a"))
+
+	err := router.Register(
+		"/foo",
+		&SimpleGetSpec{},
+		WithMethodHandler("PROPFIND", makeEmptyHandler(http.StatusMultiStatus)),
+		WithMiddleware(middlewareFactory("b")),
+		WithMiddlewareFor(middlewareFactory("c"), RegisterMethod("PROPFIND")),
+	)
+
+	Expect(err).To(BeNil())
+
+	req, _ := http.NewRequest("PROPFIND", "/foo", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusMultiStatus))
+	Expect(calls).To(Equal([]string{"a", "b", "c"}))
+
+	req, _ = http.NewRequest("OPTIONS", "/foo", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.HeaderMap.Get("Allow")).To(Equal("GET, HEAD, OPTIONS, PROPFIND"))
+
+	// Unregistered extension methods are not implemented
+	req, _ = http.NewRequest("QUERY", "/foo", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+}
+
+func (s *RouterSuite) TestRequestContext(t sweet.T) {
+	for _, usePipeline := range []bool{false, true} {
+		var (
+			container = nacelle.NewServiceContainer()
+			logger    = nacelle.NewNilLogger()
+			router    = NewRouter(container, logger)
+			started   = make(chan struct{})
+		)
+
+		if usePipeline {
+			Expect(router.Use(MiddlewareFunc(func(h Handler) (Handler, error) { return h, nil }))).To(BeNil())
+		}
+
+		spec := &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+			close(started)
+			<-ctx.Done()
+
+			// Values of the router and of the request are both available
+			if _, err := GetURL(ctx, "user", "id", "123"); err != nil {
+				return response.Empty(http.StatusInternalServerError)
+			}
+
+			if ctx.Value(testToken("request")) != "value" {
+				return response.Empty(http.StatusInternalServerError)
+			}
+
+			return response.Empty(http.StatusServiceUnavailable).SetHeader("X-Error", ctx.Err().Error())
+		}}
+
+		Expect(router.Register("/users", spec)).To(BeNil())
+		Expect(router.Register("/users/{id}", &SimpleGetSpec{}, WithName("user"))).To(BeNil())
+
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), testToken("request"), "value"))
+		req, _ := http.NewRequest("GET", "/users", nil)
+		recorder := httptest.NewRecorder()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			router.ServeHTTP(recorder, req.WithContext(ctx))
+		}()
+
+		Eventually(started).Should(BeClosed())
+		cancel()
+		Eventually(done).Should(BeClosed())
+
+		Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(recorder.HeaderMap.Get("X-Error")).To(Equal(context.Canceled.Error()))
+	}
+}
+
+func (s *RouterSuite) TestUse(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+		calls     = []string{}
+	)
+
+	middlewareFactory := func(name string) Middleware {
+		return MiddlewareFunc(func(h Handler) (Handler, error) {
+			handler := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+				calls = append(calls, name)
+				resp := h(context.WithValue(ctx, testToken(name), name), r, logger)
+				resp.AddHeader("X-Middleware", name)
+				return resp
+			}
+
+			return handler, nil
+		})
+	}
+
+	spec := &HandlerSpec{handler: func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return response.JSON([]interface{}{ctx.Value(testToken("a")), ctx.Value(testToken("b"))})
+	}}
+
+	// Register resources before and after middleware
+	Expect(router.Register("/foo", spec)).To(BeNil())
+	Expect(router.Use(middlewareFactory("a"))).To(BeNil())
+	Expect(router.Group("/api").Use(middlewareFactory("b"))).To(BeNil())
+	Expect(router.Register("/bar", &SimpleGetSpec{})).To(BeNil())
+
+	router.RegisterHandler("/raw", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Raw", "raw")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("raw body"))
+	}))
+
+	testCases := []struct {
+		method string
+		url    string
+		status int
+	}{
+		{"GET", "/foo", http.StatusOK},
+		{"GET", "/bar", http.StatusNoContent},
+		{"POST", "/bar", http.StatusMethodNotAllowed},
+		{"GET", "/baz", http.StatusNotFound},
+		{"GET", "/raw", http.StatusAccepted},
+	}
+
+	for _, testCase := range testCases {
+		calls = calls[:0]
+
+		req, _ := http.NewRequest(testCase.method, testCase.url, nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		Expect(recorder.Code).To(Equal(testCase.status))
+		Expect(recorder.HeaderMap["X-Middleware"]).To(Equal([]string{"b", "a"}))
+		Expect(calls).To(Equal([]string{"a", "b"}))
+
+		switch testCase.url {
+		case "/foo":
+			Expect(recorder.Body.String()).To(MatchJSON(`["a", "b"]`))
+		case "/raw":
+			Expect(recorder.HeaderMap.Get("X-Raw")).To(Equal("raw"))
+			Expect(recorder.Body.String()).To(Equal("raw body"))
+		}
+	}
+}
+
+func (s *RouterSuite) TestUseError(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	middleware := MiddlewareFunc(func(h Handler) (Handler, error) {
+		return nil, fmt.Errorf("utoh")
+	})
+
+	Expect(router.Use(middleware)).To(MatchError("utoh"))
+	Expect(router.Register("/foo", &SimpleGetSpec{})).To(BeNil())
+
+	// Failed middleware is not registered
+	req, _ := http.NewRequest("GET", "/foo", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusNoContent))
+}
+
+func (s *RouterSuite) TestRegisterHandlerWithMiddleware(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+		statuses  = []int{}
+	)
+
+	middleware := MiddlewareFunc(func(h Handler) (Handler, error) {
+		handler := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+			resp := h(ctx, r, logger)
+			statuses = append(statuses, resp.StatusCode())
+			return resp.SetHeader("X-Middleware", "yes")
+		}
+
+		return handler, nil
+	})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("hello"))
+	})
+
+	Expect(router.RegisterHandler("/foo", handler, WithMiddlewareFor(middleware, MethodPost))).To(BeNil())
+
+	req, _ := http.NewRequest("POST", "/foo", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusAccepted))
+	Expect(recorder.HeaderMap.Get("X-Middleware")).To(Equal("yes"))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal("text/plain"))
+	Expect(recorder.Body.String()).To(Equal("hello"))
+	Expect(statuses).To(Equal([]int{http.StatusAccepted}))
+
+	// Undecorated methods still reach the handler
+	req, _ = http.NewRequest("GET", "/foo", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusAccepted))
+	Expect(recorder.HeaderMap.Get("X-Middleware")).To(BeEmpty())
+	Expect(recorder.Body.String()).To(Equal("hello"))
+}
+
+func (s *RouterSuite) TestRegisterHandlerWithMiddlewareContext(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	middleware := MiddlewareFunc(func(h Handler) (Handler, error) {
+		handler := func(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+			return h(context.WithValue(ctx, "X-Middleware", "yes"), r, logger)
+		}
+
+		return handler, nil
+	})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Middleware", r.Context().Value("X-Middleware").(string))
+		w.Header().Set("X-Id", PathParam(r.Context(), "id"))
+		w.WriteHeader(http.StatusAccepted)
+		w.Header().Set("X-Late", "yes")
+		w.Write([]byte("hello"))
+	})
+
+	Expect(router.RegisterHandler("/foo/{id}", handler, WithMiddleware(middleware))).To(BeNil())
+
+	req, _ := http.NewRequest("GET", "/foo/123", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusAccepted))
+	Expect(recorder.HeaderMap.Get("X-Middleware")).To(Equal("yes"))
+	Expect(recorder.HeaderMap.Get("X-Id")).To(Equal("123"))
+	Expect(recorder.HeaderMap.Get("X-Late")).To(BeEmpty())
+	Expect(recorder.Body.String()).To(Equal("hello"))
+}
+
+func (s *RouterSuite) TestRegisterHandlerWithMiddlewareError(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	middleware := MiddlewareFunc(func(h Handler) (Handler, error) {
+		return nil, fmt.Errorf("utoh")
+	})
+
+	Expect(router.RegisterHandler("/foo", http.NotFoundHandler(), WithMiddleware(middleware))).To(MatchError("utoh"))
+}
+
+func (s *RouterSuite) TestRegisterWithMatchers(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	Expect(router.Register("/users", &HandlerSpec{handler: makeEmptyHandler(200)}, WithHost("api.example.com"))).To(BeNil())
+	Expect(router.Register("/users", &HandlerSpec{handler: makeEmptyHandler(201)}, WithHost("internal.example.com"))).To(BeNil())
+	Expect(router.Register("/users", &HandlerSpec{handler: makeEmptyHandler(202)}, WithHeaders("X-Version", "2"))).To(BeNil())
+	Expect(router.Register("/users", &HandlerSpec{handler: makeEmptyHandler(203)}, WithQueries("v", "{v:[0-9]+}"))).To(BeNil())
+	Expect(router.Register("/users", &HandlerSpec{handler: makeEmptyHandler(204)}, WithSchemes("https"))).To(BeNil())
+
+	testCases := []struct {
+		url     string
+		headers map[string]string
+		status  int
+	}{
+		{"http://api.example.com/users", nil, 200},
+		{"http://internal.example.com/users", nil, 201},
+		{"http://other.example.com/users", map[string]string{"X-Version": "2"}, 202},
+		{"http://other.example.com/users?v=3", nil, 203},
+		{"https://other.example.com/users", nil, 204},
+		{"http://other.example.com/users", nil, 404},
+		{"http://other.example.com/users?v=x", nil, 404},
+	}
+
+	for _, testCase := range testCases {
+		req, _ := http.NewRequest("GET", testCase.url, nil)
+		for key, value := range testCase.headers {
+			req.Header.Set(key, value)
+		}
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		Expect(recorder.Code).To(Equal(testCase.status))
+	}
+
+	Expect(router.Routes()[0].Host).To(Equal("api.example.com"))
+	Expect(router.Routes()[3].Queries).To(Equal([]string{"v", "{v:[0-9]+}"}))
+}
+
+func (s *RouterSuite) TestRegisterDuplicateMatchers(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger)
+	)
+
+	err1 := router.Register("/users", &EmptySpec{}, WithHost("api.example.com"), WithHeaders("X-A", "1", "x-b", "2"))
+	err2 := router.Register("/users", &EmptySpec{}, WithHost("API.example.com"), WithHeaders("X-B", "2", "X-A", "1"))
+	err3 := router.Register("/users", &EmptySpec{}, WithHost("api.example.com"))
+
+	Expect(err1).To(BeNil())
+	Expect(err2).NotTo(BeNil())
+	Expect(err2.Error()).To(Equal("resource already registered to url pattern `/users` with host `api.example.com`, headers `X-A=1,X-B=2`"))
+	Expect(err3).To(BeNil())
+}
+
+func (s *RouterSuite) TestRegisterInvalidMatchers(t sweet.T) {
+	var (
+		container = nacelle.NewServiceContainer()
+		logger    = nacelle.NewNilLogger()
+		router    = NewRouter(container, logger).(*router)
+	)
+
+	Expect(router.Register("/users", &EmptySpec{}, WithQueries("v"))).NotTo(BeNil())
+	Expect(router.Register("/users", &SimpleGetSpec{}, WithName("users"), WithHost("{api"))).NotTo(BeNil())
+
+	// Failed routes are not attached to the mux
+	numRoutes := 0
+	router.mux.Walk(func(*mux.Route, *mux.Router, []*mux.Route) error {
+		numRoutes++
+		return nil
+	})
+	Expect(numRoutes).To(BeZero())
+
+	Expect(router.Register("/users", &SimpleGetSpec{}, WithName("users"))).To(BeNil())
+	Expect(router.Routes()).To(HaveLen(1))
+
+	url, err := router.URL("users")
+	Expect(err).To(BeNil())
+	Expect(url.String()).To(Equal("/users"))
+
+	req, _ := http.NewRequest("GET", "/users", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusNoContent))
+}
+
 //
 //
 
@@ -121,6 +756,8 @@ type ServiceSpec struct {
 	C string `service:"c"`
 }
 
+type testToken string
+
 type SimpleGetSpec struct {
 	*EmptySpec
 }
@@ -128,3 +765,11 @@ type SimpleGetSpec struct {
 func (s *SimpleGetSpec) Get(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
 	return response.Empty(http.StatusNoContent)
 }
+
+type HeadSpec struct {
+	*SimpleGetSpec
+}
+
+func (s *HeadSpec) Head(ctx context.Context, r *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusOK).SetHeader("X-Head", "spec")
+}
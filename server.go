@@ -0,0 +1,50 @@
+package chevron
+
+import (
+	"github.com/go-nacelle/httpbase"
+	"github.com/go-nacelle/nacelle"
+)
+
+// Server is a nacelle process that serves HTTP. It behaves like the
+// server created by httpbase.NewServer, except that a server created
+// with a ServerInitializer drains its router before shutting down.
+type Server struct {
+	Services    nacelle.ServiceContainer `service:"services"`
+	server      *httpbase.Server
+	initializer httpbase.ServerInitializer
+}
+
+// NewServer creates a new Server with the given initializer and configs.
+func NewServer(initializer httpbase.ServerInitializer, configs ...httpbase.ConfigFunc) *Server {
+	return &Server{
+		server:      httpbase.NewServer(initializer, configs...),
+		initializer: initializer,
+	}
+}
+
+// Init initializes the underlying HTTP server.
+func (s *Server) Init(config nacelle.Config) error {
+	if err := s.Services.Inject(s.server); err != nil {
+		return err
+	}
+
+	return s.server.Init(config)
+}
+
+// Start serves HTTP until the server is stopped.
+func (s *Server) Start() error {
+	return s.server.Start()
+}
+
+// Stop drains the router, then shuts down the underlying HTTP server. The
+// readiness endpoint reports failure immediately, but requests continue to
+// be served for the drain delay of the server config. New requests are then
+// rejected with a 503-level response while the requests in flight complete.
+// The latter wait is bounded by the drain timeout of the server config.
+func (s *Server) Stop() error {
+	if initializer, ok := s.initializer.(*ServerInitializer); ok {
+		initializer.drain()
+	}
+
+	return s.server.Stop()
+}
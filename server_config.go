@@ -0,0 +1,107 @@
+package chevron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// ServerConfig controls the router behaviors and built-in middleware of
+	// a server created by ServerInitializer from environment variables (or
+	// configuration files), so that they can be changed without a rebuild.
+	ServerConfig struct {
+		RequestID           bool     `env:"chevron_request_id" file:"chevron_request_id" default:"false"`
+		Logging             bool     `env:"chevron_logging" file:"chevron_logging" default:"false"`
+		Recovery            bool     `env:"chevron_recovery" file:"chevron_recovery" default:"false"`
+		Gzip                bool     `env:"chevron_gzip" file:"chevron_gzip" default:"false"`
+		GzipLevel           int      `env:"chevron_gzip_level" file:"chevron_gzip_level" default:"-1"`
+		RawRequestTimeout   int      `env:"chevron_request_timeout" file:"chevron_request_timeout" default:"0"`
+		CORSOrigins         []string `env:"chevron_cors_origins" file:"chevron_cors_origins"`
+		RawTrailingSlash    string   `env:"chevron_trailing_slash" file:"chevron_trailing_slash" default:"strict"`
+		RawCleanPath        string   `env:"chevron_clean_path" file:"chevron_clean_path" default:"redirect"`
+		RawCaseInsensitive  string   `env:"chevron_case_insensitive" file:"chevron_case_insensitive" default:"strict"`
+		ProblemDetails      bool     `env:"chevron_problem_details" file:"chevron_problem_details" default:"false"`
+		RawDrainTimeout     int      `env:"chevron_drain_timeout" file:"chevron_drain_timeout" default:"10"`
+		RawDrainDelay       int      `env:"chevron_drain_delay" file:"chevron_drain_delay" default:"0"`
+		RequestTimeout      time.Duration
+		DrainTimeout        time.Duration
+		DrainDelay          time.Duration
+		TrailingSlashPolicy PathPolicy
+		CleanPathPolicy     PathPolicy
+		CaseInsensitive     PathPolicy
+	}
+
+	// ServerMiddlewareFactory creates the middleware enabled by the given
+	// server config. The returned middleware wraps the router's entire
+	// dispatch pipeline, with the first middleware outermost.
+	ServerMiddlewareFactory func(config *ServerConfig) ([]Middleware, error)
+)
+
+var pathPolicyNames = map[string]PathPolicy{
+	"strict":   PathPolicyStrict,
+	"redirect": PathPolicyRedirect,
+	"match":    PathPolicyMatch,
+}
+
+// PostLoad validates and converts the raw values of the config.
+func (c *ServerConfig) PostLoad() error {
+	if c.RawRequestTimeout < 0 {
+		return fmt.Errorf("request timeout must be non-negative")
+	}
+
+	if c.RawDrainTimeout < 0 {
+		return fmt.Errorf("drain timeout must be non-negative")
+	}
+
+	if c.RawDrainDelay < 0 {
+		return fmt.Errorf("drain delay must be non-negative")
+	}
+
+	c.RequestTimeout = time.Duration(c.RawRequestTimeout) * time.Second
+	c.DrainTimeout = time.Duration(c.RawDrainTimeout) * time.Second
+	c.DrainDelay = time.Duration(c.RawDrainDelay) * time.Second
+
+	policies := []struct {
+		name   string
+		raw    string
+		policy *PathPolicy
+	}{
+		{"trailing slash", c.RawTrailingSlash, &c.TrailingSlashPolicy},
+		{"clean path", c.RawCleanPath, &c.CleanPathPolicy},
+		{"case insensitive", c.RawCaseInsensitive, &c.CaseInsensitive},
+	}
+
+	for _, p := range policies {
+		policy, ok := pathPolicyNames[strings.ToLower(p.raw)]
+		if !ok {
+			return fmt.Errorf("illegal %s policy `%s` (expected strict, redirect, or match)", p.name, p.raw)
+		}
+
+		*p.policy = policy
+	}
+
+	return nil
+}
+
+// MiddlewareEnabled determines if the config enables any of the built-in
+// middleware.
+func (c *ServerConfig) MiddlewareEnabled() bool {
+	return c.RequestID || c.Logging || c.Recovery || c.Gzip || c.RequestTimeout > 0 || len(c.CORSOrigins) > 0
+}
+
+// routerConfigs returns the router configs controlled by the config.
+func (c *ServerConfig) routerConfigs() []RouterConfigFunc {
+	configs := []RouterConfigFunc{
+		WithTrailingSlashPolicy(c.TrailingSlashPolicy),
+		WithCleanPathPolicy(c.CleanPathPolicy),
+		WithCaseInsensitivePolicy(c.CaseInsensitive),
+		WithDrainDelay(c.DrainDelay),
+	}
+
+	if c.ProblemDetails {
+		configs = append(configs, WithProblemDetails())
+	}
+
+	return configs
+}
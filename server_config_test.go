@@ -0,0 +1,149 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/config"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type ServerConfigSuite struct{}
+
+func (s *ServerConfigSuite) TestDefaults(t sweet.T) {
+	serverConfig := &ServerConfig{}
+	Expect(loadServerConfig(nil).Load(serverConfig)).To(BeNil())
+	Expect(serverConfig.RequestTimeout).To(Equal(time.Duration(0)))
+	Expect(serverConfig.DrainTimeout).To(Equal(10 * time.Second))
+	Expect(serverConfig.DrainDelay).To(Equal(time.Duration(0)))
+	Expect(serverConfig.TrailingSlashPolicy).To(Equal(PathPolicyStrict))
+	Expect(serverConfig.CleanPathPolicy).To(Equal(PathPolicyRedirect))
+	Expect(serverConfig.CaseInsensitive).To(Equal(PathPolicyStrict))
+	Expect(serverConfig.MiddlewareEnabled()).To(BeFalse())
+}
+
+func (s *ServerConfigSuite) TestLoad(t sweet.T) {
+	serverConfig := &ServerConfig{}
+	Expect(loadServerConfig(map[string]string{
+		"chevron_request_timeout":  "5",
+		"chevron_cors_origins":     `["https://example.com"]`,
+		"chevron_trailing_slash":   "Match",
+		"chevron_case_insensitive": "redirect",
+		"chevron_drain_delay":      "3",
+	}).Load(serverConfig)).To(BeNil())
+
+	Expect(serverConfig.RequestTimeout).To(Equal(5 * time.Second))
+	Expect(serverConfig.CORSOrigins).To(ConsistOf("https://example.com"))
+	Expect(serverConfig.TrailingSlashPolicy).To(Equal(PathPolicyMatch))
+	Expect(serverConfig.CaseInsensitive).To(Equal(PathPolicyRedirect))
+	Expect(serverConfig.DrainDelay).To(Equal(3 * time.Second))
+	Expect(serverConfig.MiddlewareEnabled()).To(BeTrue())
+}
+
+func (s *ServerConfigSuite) TestLoadIllegalPolicy(t sweet.T) {
+	err := loadServerConfig(map[string]string{"chevron_clean_path": "sometimes"}).Load(&ServerConfig{})
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(ContainSubstring("illegal clean path policy `sometimes`"))
+}
+
+func (s *ServerConfigSuite) TestInitAppliesRouterConfigs(t sweet.T) {
+	server := &http.Server{}
+	Expect(initServer(map[string]string{
+		"chevron_trailing_slash":  "match",
+		"chevron_problem_details": "true",
+	}, server)).To(BeNil())
+
+	recorder := serveRequest(server.Handler, "GET", "/users/", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+
+	recorder = serveRequest(server.Handler, "GET", "/missing", nil)
+	Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal(ProblemContentType))
+}
+
+func (s *ServerConfigSuite) TestInitRouterConfigPrecedence(t sweet.T) {
+	server := &http.Server{}
+	Expect(initServer(map[string]string{
+		"chevron_trailing_slash": "match",
+	}, server, WithTrailingSlashPolicy(PathPolicyStrict))).To(BeNil())
+
+	Expect(serveRequest(server.Handler, "GET", "/users/", nil).Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *ServerConfigSuite) TestInitConfigTagModifiers(t sweet.T) {
+	server := &http.Server{}
+	Expect(initServer(map[string]string{
+		"chevron_trailing_slash":       "strict",
+		"admin_chevron_trailing_slash": "match",
+	}, server, WithConfigTagModifiers(nacelle.NewEnvTagPrefixer("admin")))).To(BeNil())
+
+	Expect(serveRequest(server.Handler, "GET", "/users/", nil).Code).To(Equal(http.StatusOK))
+}
+
+func (s *ServerConfigSuite) TestInitMiddlewareWithoutFactory(t sweet.T) {
+	err := initServer(map[string]string{"chevron_gzip": "true"}, &http.Server{})
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(ContainSubstring("no middleware factory is registered"))
+}
+
+func (s *ServerConfigSuite) TestInitMiddlewareFactory(t sweet.T) {
+	var (
+		server = &http.Server{}
+		loaded *ServerConfig
+	)
+
+	factory := func(config *ServerConfig) ([]Middleware, error) {
+		loaded = config
+
+		return []Middleware{
+			MiddlewareFunc(func(f Handler) (Handler, error) {
+				return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+					resp := f(ctx, req, logger)
+					resp.SetHeader("X-Recovered", "true")
+					return resp
+				}, nil
+			}),
+		}, nil
+	}
+
+	Expect(initServer(map[string]string{"chevron_recovery": "true"}, server, WithServerMiddleware(factory))).To(BeNil())
+	Expect(loaded).NotTo(BeNil())
+	Expect(loaded.Recovery).To(BeTrue())
+
+	recorder := serveRequest(server.Handler, "GET", "/users", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.HeaderMap.Get("X-Recovered")).To(Equal("true"))
+}
+
+func (s *ServerConfigSuite) TestInitMiddlewareFactoryNotCalledWhenDisabled(t sweet.T) {
+	called := false
+	factory := func(config *ServerConfig) ([]Middleware, error) {
+		called = true
+		return nil, nil
+	}
+
+	Expect(initServer(nil, &http.Server{}, WithServerMiddleware(factory))).To(BeNil())
+	Expect(called).To(BeFalse())
+}
+
+//
+//
+
+func loadServerConfig(values map[string]string) nacelle.Config {
+	return nacelle.NewConfig(config.NewTestEnvSourcer(values))
+}
+
+func initServer(values map[string]string, server *http.Server, configs ...InitializerConfig) error {
+	initializer := NewInitializer(RouteInitializerFunc(func(config nacelle.Config, router Router) error {
+		return router.Register("/users", &EmptySpec{}, WithMethodHandler("GET", makeEmptyHandler(http.StatusOK)))
+	}), configs...).(*ServerInitializer)
+
+	initializer.Services = nacelle.NewServiceContainer()
+	initializer.Logger = nacelle.NewNilLogger()
+
+	return initializer.Init(loadServerConfig(values), server)
+}
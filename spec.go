@@ -3,6 +3,8 @@ package chevron
 import (
 	"context"
 	"net/http"
+	"reflect"
+	"runtime"
 
 	"github.com/efritz/response"
 	"github.com/go-nacelle/nacelle"
@@ -36,6 +38,19 @@ type (
 	// first embedded field in any resource - this allows a resource to simply
 	// "override" the handlers for methods relevant to a resource.
 	EmptySpec struct{}
+
+	// ImplementedMethodsSpec is a ResourceSpec which declares the HTTP methods
+	// for which it supplies a handler (other than the one inherited from
+	// EmptySpec). These methods populate the Allow header and the automatic
+	// response to OPTIONS requests. Otherwise, the router infers the methods
+	// from the method set of the spec, which is not possible for specs that
+	// embed an interface.
+	ImplementedMethodsSpec interface {
+		ResourceSpec
+
+		// ImplementedMethods returns the HTTP methods implemented by the spec.
+		ImplementedMethods() []Method
+	}
 )
 
 // Get invokes the router's not implemented handler.
@@ -67,3 +82,137 @@ func (es *EmptySpec) Patch(ctx context.Context, req *http.Request, logger nacell
 func (es *EmptySpec) Delete(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
 	return GetNotImplementedHandler(ctx)(ctx, req, logger)
 }
+
+var specMethodNames = map[Method]string{
+	MethodGet:     "Get",
+	MethodOptions: "Options",
+	MethodPost:    "Post",
+	MethodPut:     "Put",
+	MethodPatch:   "Patch",
+	MethodDelete:  "Delete",
+}
+
+var emptySpecType = reflect.TypeOf(&EmptySpec{})
+
+// headSpec is implemented by resource specs which handle HEAD requests
+// themselves rather than deriving the response from the GET handler.
+type headSpec interface {
+	Head(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response
+}
+
+// implementedMethods returns the HTTP methods for which the given spec
+// supplies a handler other than the one inherited from EmptySpec.
+func implementedMethods(spec ResourceSpec) []Method {
+	methods := []Method{}
+
+	if declared, ok := spec.(ImplementedMethodsSpec); ok {
+		for _, method := range declared.ImplementedMethods() {
+			if _, ok := specMethodNames[method]; ok {
+				methods = insertMethod(methods, method)
+			}
+		}
+
+		return methods
+	}
+
+	for _, method := range allMethods {
+		name, ok := specMethodNames[method]
+		if !ok {
+			continue
+		}
+
+		if implementsMethod(reflect.TypeOf(spec), name) {
+			methods = append(methods, method)
+		}
+	}
+
+	return methods
+}
+
+// implementsMethod determines if the method with the given name is declared
+// on the given type, or is promoted from an embedded field that is not an
+// EmptySpec and itself implements the method. A method promoted from an
+// embedded interface is assumed to be implemented, as the value supplying
+// the method is not known until the spec is invoked.
+func implementsMethod(t reflect.Type, name string) bool {
+	if t == emptySpecType || t == emptySpecType.Elem() {
+		return false
+	}
+
+	if _, ok := t.MethodByName(name); !ok {
+		return false
+	}
+
+	if declaresMethod(t, name) {
+		return true
+	}
+
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+
+	if st.Kind() != reflect.Struct {
+		return true
+	}
+
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+
+		ft := field.Type
+		if _, ok := ft.MethodByName(name); !ok && ft.Kind() != reflect.Ptr && t.Kind() == reflect.Ptr {
+			ft = reflect.PtrTo(ft)
+		}
+
+		if _, ok := ft.MethodByName(name); ok {
+			if ft.Kind() == reflect.Interface {
+				return true
+			}
+
+			return implementsMethod(ft, name)
+		}
+	}
+
+	return true
+}
+
+// declaresMethod determines if the method with the given name is declared
+// on the given type (with either a value or a pointer receiver) rather than
+// promoted from an embedded field. The method sets of both the value and the
+// pointer type are consulted, as the compiler generates a pointer wrapper for
+// each method with a value receiver.
+func declaresMethod(t reflect.Type, name string) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for _, candidate := range []reflect.Type{t, reflect.PtrTo(t)} {
+		if m, ok := candidate.MethodByName(name); ok && !isWrapper(m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isWrapper determines if the given method is a compiler-generated wrapper,
+// either around a method of an embedded field or around a method with a value
+// receiver. This relies on the runtime reporting such wrappers as declared in
+// an autogenerated file; specs can avoid this inference entirely by conforming
+// to ImplementedMethodsSpec.
+func isWrapper(m reflect.Method) bool {
+	if !m.Func.IsValid() {
+		return false
+	}
+
+	f := runtime.FuncForPC(m.Func.Pointer())
+	if f == nil {
+		return false
+	}
+
+	file, _ := f.FileLine(f.Entry())
+	return file == "<autogenerated>"
+}
@@ -32,6 +32,51 @@ func (s *SpecSuite) TestEmptySpecComposition(t sweet.T) {
 	}
 }
 
+func (s *SpecSuite) TestImplementedMethods(t sweet.T) {
+	Expect(implementedMethods(&EmptySpec{})).To(BeEmpty())
+	Expect(implementedMethods(&TestSpec{})).To(Equal([]Method{MethodGet}))
+	Expect(implementedMethods(&DerivedSpec{})).To(Equal([]Method{MethodGet, MethodPost}))
+	Expect(implementedMethods(&FullSpec{})).To(Equal([]Method{
+		MethodGet,
+		MethodOptions,
+		MethodPost,
+		MethodPut,
+		MethodPatch,
+		MethodDelete,
+	}))
+}
+
+func (s *SpecSuite) TestImplementedMethodsValueReceiver(t sweet.T) {
+	Expect(implementedMethods(&ValueSpec{})).To(Equal([]Method{MethodGet}))
+	Expect(implementedMethods(&DerivedValueSpec{})).To(Equal([]Method{MethodGet, MethodPost}))
+}
+
+func (s *SpecSuite) TestImplementedMethodsEmbeddedInterface(t sweet.T) {
+	Expect(implementedMethods(&InterfaceSpec{ResourceSpec: &TestSpec{}})).To(Equal([]Method{
+		MethodGet,
+		MethodOptions,
+		MethodPost,
+		MethodPut,
+		MethodPatch,
+		MethodDelete,
+	}))
+}
+
+func (s *SpecSuite) TestImplementedMethodsDeclared(t sweet.T) {
+	spec := &DeclaredSpec{TestSpec: &TestSpec{}, methods: []Method{MethodPost, MethodGet, MethodHead}}
+	Expect(implementedMethods(spec)).To(Equal([]Method{MethodGet, MethodPost}))
+
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(router.Register("/declared", &DeclaredSpec{TestSpec: &TestSpec{}, methods: []Method{MethodGet}})).To(BeNil())
+	Expect(router.Routes()[0].Methods).To(Equal([]string{"GET", "HEAD", "OPTIONS"}))
+}
+
+func (s *SpecSuite) TestRegisterValueReceiver(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(router.Register("/values", &ValueSpec{})).To(BeNil())
+	Expect(router.Routes()[0].Methods).To(Equal([]string{"GET", "HEAD", "OPTIONS"}))
+}
+
 func testBackground() context.Context {
 	return setNotImplementedHandler(context.Background(), defaultNotImplementedHandler)
 }
@@ -46,3 +91,66 @@ type TestSpec struct {
 func (ts *TestSpec) Get(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
 	return response.JSON([]string{"foo", "bar", "baz"})
 }
+
+type DerivedSpec struct {
+	*TestSpec
+}
+
+func (ds *DerivedSpec) Post(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusCreated)
+}
+
+type FullSpec struct{}
+
+func (fs *FullSpec) Get(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusOK)
+}
+
+func (fs *FullSpec) Options(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusOK)
+}
+
+func (fs *FullSpec) Post(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusOK)
+}
+
+func (fs *FullSpec) Put(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusOK)
+}
+
+func (fs *FullSpec) Patch(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusOK)
+}
+
+func (fs *FullSpec) Delete(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusOK)
+}
+
+type ValueSpec struct {
+	EmptySpec
+}
+
+func (vs ValueSpec) Get(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusOK)
+}
+
+type DerivedValueSpec struct {
+	ValueSpec
+}
+
+func (ds *DerivedValueSpec) Post(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	return response.Empty(http.StatusCreated)
+}
+
+type InterfaceSpec struct {
+	ResourceSpec
+}
+
+type DeclaredSpec struct {
+	*TestSpec
+	methods []Method
+}
+
+func (ds *DeclaredSpec) ImplementedMethods() []Method {
+	return ds.methods
+}
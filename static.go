@@ -0,0 +1,309 @@
+package chevron
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+)
+
+type (
+	// StaticSpec is a ResourceSpec that serves the contents of a file system.
+	StaticSpec struct {
+		*EmptySpec
+		fsys             fs.FS
+		pathParam        string
+		indexFile        string
+		spaFallback      bool
+		cacheControl     map[string]string
+		precompressed    bool
+		directoryListing bool
+		etags            map[string]etagEntry
+		etagsMutex       sync.Mutex
+	}
+
+	// etagEntry is the cached entity tag of a file, which is valid as long as
+	// the size and modification time of the file do not change.
+	etagEntry struct {
+		size    int64
+		modTime time.Time
+		etag    string
+	}
+)
+
+// maxCachedETags is the number of files whose entity tags are cached by a
+// single static spec.
+const maxCachedETags = 1024
+
+// NewStaticSpec creates a ResourceSpec that serves files from the given file
+// system (such as an embed.FS). The spec is generally registered to a pattern
+// ending in a variable that matches the remainder of the path:
+//
+//	router.Register("/app/{path:.*}", chevron.NewStaticSpec(fsys))
+//
+// Responses carry a strong ETag and, if the file system supplies modification
+// times, a Last-Modified header. Conditional and byte range requests are
+// supported. Directory listing is disabled unless WithDirectoryListing is
+// supplied. File content is read from the file system as it is served, but
+// the portion of the file sent in a response is buffered in memory along with
+// the rest of the response so that middleware can decorate it.
+func NewStaticSpec(fsys fs.FS, configs ...StaticConfigFunc) ResourceSpec {
+	spec := &StaticSpec{
+		fsys:         fsys,
+		pathParam:    "path",
+		indexFile:    "index.html",
+		cacheControl: map[string]string{},
+		etags:        map[string]etagEntry{},
+	}
+
+	for _, f := range configs {
+		f(spec)
+	}
+
+	return spec
+}
+
+// Get serves the requested file.
+func (s *StaticSpec) Get(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+	name := s.requestedName(ctx, req)
+
+	resp, err := s.serve(ctx, req, logger, name)
+	if errors.Is(err, fs.ErrNotExist) && s.spaFallback && (path.Ext(name) == "" || acceptsHTML(req)) {
+		resp, err = s.serve(ctx, req, logger, s.indexFile)
+	}
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return DefaultResponse(ctx, req, http.StatusNotFound)
+		}
+
+		logger.Error("Failed to serve static file `%s` (%s)", name, err.Error())
+		return DefaultResponse(ctx, req, http.StatusInternalServerError)
+	}
+
+	return resp
+}
+
+// requestedName returns the path of the requested file relative to the root
+// of the file system.
+func (s *StaticSpec) requestedName(ctx context.Context, req *http.Request) string {
+	name, ok := PathParams(ctx)[s.pathParam]
+	if !ok {
+		name = req.URL.Path
+	}
+
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+
+	return name
+}
+
+func (s *StaticSpec) serve(ctx context.Context, req *http.Request, logger nacelle.Logger, name string) (response.Response, error) {
+	info, err := fs.Stat(s.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		indexName := path.Join(name, s.indexFile)
+		if _, err := fs.Stat(s.fsys, indexName); err == nil {
+			return s.serveFile(ctx, req, logger, indexName)
+		}
+
+		if !s.directoryListing {
+			return nil, fs.ErrNotExist
+		}
+
+		return s.serveListing(req, name)
+	}
+
+	return s.serveFile(ctx, req, logger, name)
+}
+
+func (s *StaticSpec) serveFile(ctx context.Context, req *http.Request, logger nacelle.Logger, name string) (response.Response, error) {
+	servedName, encoding := name, ""
+	if s.precompressed && acceptsGzip(req) {
+		if info, err := fs.Stat(s.fsys, name+".gz"); err == nil && !info.IsDir() {
+			servedName, encoding = name+".gz", "gzip"
+		}
+	}
+
+	file, err := s.fsys.Open(servedName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := seekableContent(file)
+	if err != nil {
+		return nil, err
+	}
+
+	etag, err := s.etag(servedName, info, content)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+
+		if value, ok := s.cacheControl[path.Ext(name)]; ok {
+			w.Header().Set("Cache-Control", value)
+		}
+
+		if s.precompressed {
+			w.Header().Set("Vary", "Accept-Encoding")
+		}
+
+		if encoding != "" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+
+		// ServeContent infers the content type from the original name
+		http.ServeContent(w, r, name, info.ModTime(), content)
+	})
+
+	return WrapHandler(handler)(ctx, req, logger), nil
+}
+
+// seekableContent returns the content of the given file as an io.ReadSeeker.
+// The content of a file which does not support seeking is read into memory.
+func seekableContent(file fs.File) (io.ReadSeeker, error) {
+	if content, ok := file.(io.ReadSeeker); ok {
+		return content, nil
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(content), nil
+}
+
+// etag returns a strong entity tag computed from the content of the file and
+// rewinds the content. Tags are cached for files whose size and modification
+// time have not changed. Once maxCachedETags files are cached, an arbitrary
+// entry is evicted for each new file.
+func (s *StaticSpec) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	s.etagsMutex.Lock()
+	entry, ok := s.etags[name]
+	s.etagsMutex.Unlock()
+
+	if ok && entry.size == info.Size() && entry.modTime.Equal(info.ModTime()) {
+		return entry.etag, nil
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	etag := fmt.Sprintf(`"%s"`, hex.EncodeToString(hash.Sum(nil)[:16]))
+
+	s.etagsMutex.Lock()
+	defer s.etagsMutex.Unlock()
+
+	if _, ok := s.etags[name]; !ok && len(s.etags) >= maxCachedETags {
+		for key := range s.etags {
+			delete(s.etags, key)
+			break
+		}
+	}
+
+	s.etags[name] = etagEntry{size: info.Size(), modTime: info.ModTime(), etag: etag}
+	return etag, nil
+}
+
+func (s *StaticSpec) serveListing(req *http.Request, name string) (response.Response, error) {
+	entries, err := fs.ReadDir(s.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("<!doctype html>\n<pre>\n")
+
+	for _, entry := range entries {
+		entryName := entry.Name()
+		if entry.IsDir() {
+			entryName += "/"
+		}
+
+		href := (&url.URL{Path: path.Join(req.URL.Path, entryName)}).String()
+		if entry.IsDir() {
+			href += "/"
+		}
+
+		fmt.Fprintf(buffer, "<a href=\"%s\">%s</a>\n", html.EscapeString(href), html.EscapeString(entryName))
+	}
+
+	buffer.WriteString("</pre>\n")
+
+	return response.Respond(buffer.Bytes()).
+		SetHeader("Content-Type", "text/html; charset=utf-8"), nil
+}
+
+// acceptsHTML determines if the request's Accept header explicitly names the
+// text/html media type, as browsers do when navigating to a page.
+func acceptsHTML(req *http.Request) bool {
+	for _, r := range parseAccept(req.Header.Get("Accept")) {
+		if r.match("text", "html") == 2 && r.quality > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// acceptsGzip determines if the request's Accept-Encoding header permits
+// a gzip-encoded response.
+func acceptsGzip(req *http.Request) bool {
+	for _, part := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		params := strings.Split(part, ";")
+		if coding := strings.TrimSpace(params[0]); coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			if value := strings.TrimSpace(param); strings.HasPrefix(value, "q=") {
+				if q, err := strconv.ParseFloat(value[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if quality > 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,48 @@
+package chevron
+
+// StaticConfigFunc is a function used to configure a static spec.
+type StaticConfigFunc func(*StaticSpec)
+
+// WithStaticPathParam sets the name of the path parameter holding the
+// path of the requested file relative to the root of the file system.
+// If the route has no such parameter, the request path is used. The
+// default parameter name is `path`.
+func WithStaticPathParam(name string) StaticConfigFunc {
+	return func(s *StaticSpec) { s.pathParam = name }
+}
+
+// WithIndexFile sets the name of the file served when a directory is
+// requested. The default is index.html.
+func WithIndexFile(name string) StaticConfigFunc {
+	return func(s *StaticSpec) { s.indexFile = name }
+}
+
+// WithSPAFallback causes the index file at the root of the file system
+// to be served in place of files that do not exist, so that a single
+// page application can handle routing in the browser. The fallback is
+// applied only to paths without a file extension and to requests which
+// accept text/html, so that a missing asset (e.g. `/app.js`) is still
+// answered with a 404.
+func WithSPAFallback() StaticConfigFunc {
+	return func(s *StaticSpec) { s.spaFallback = true }
+}
+
+// WithCacheControl sets the value of the Cache-Control header of files
+// with the given extension (e.g. `.js`). The empty extension applies to
+// files without an extension.
+func WithCacheControl(extension, value string) StaticConfigFunc {
+	return func(s *StaticSpec) { s.cacheControl[extension] = value }
+}
+
+// WithPrecompressed causes a gzipped sibling of the requested file (with
+// an additional .gz extension) to be served in its place when one exists
+// and the client accepts gzip-encoded content.
+func WithPrecompressed() StaticConfigFunc {
+	return func(s *StaticSpec) { s.precompressed = true }
+}
+
+// WithDirectoryListing causes an HTML listing of a directory's contents
+// to be served when a directory without an index file is requested.
+func WithDirectoryListing() StaticConfigFunc {
+	return func(s *StaticSpec) { s.directoryListing = true }
+}
@@ -0,0 +1,221 @@
+package chevron
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing/fstest"
+	"time"
+
+	"github.com/aphistic/sweet"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type StaticSuite struct{}
+
+var (
+	staticModTime = time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	staticFS = fstest.MapFS{
+		"index.html":          {Data: []byte("<html>home</html>"), ModTime: staticModTime},
+		"app.js":              {Data: []byte("console.log('app');"), ModTime: staticModTime},
+		"app.js.gz":           {Data: []byte("gzipped app"), ModTime: staticModTime},
+		"docs/index.html":     {Data: []byte("<html>docs</html>"), ModTime: staticModTime},
+		"assets/logo.txt":     {Data: []byte("0123456789"), ModTime: staticModTime},
+		"assets/img/icon.txt": {Data: []byte("icon"), ModTime: staticModTime},
+	}
+)
+
+func (s *StaticSuite) TestServeFile(t sweet.T) {
+	router := newStaticRouter()
+
+	recorder := serveRequest(router, "GET", "/app/app.js", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(Equal("console.log('app');"))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal("text/javascript; charset=utf-8"))
+	Expect(recorder.HeaderMap.Get("Last-Modified")).To(Equal("Sat, 01 Jun 2019 12:00:00 GMT"))
+	Expect(recorder.HeaderMap.Get("ETag")).To(MatchRegexp(`^"[0-9a-f]{32}"$`))
+}
+
+func (s *StaticSuite) TestIndexFile(t sweet.T) {
+	router := newStaticRouter()
+
+	recorder := serveRequest(router, "GET", "/app/", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(Equal("<html>home</html>"))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+
+	recorder = serveRequest(router, "GET", "/app/docs", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(Equal("<html>docs</html>"))
+}
+
+func (s *StaticSuite) TestNotFound(t sweet.T) {
+	router := newStaticRouter()
+
+	Expect(serveRequest(router, "GET", "/app/missing.js", nil).Code).To(Equal(http.StatusNotFound))
+
+	router = NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithCleanPathPolicy(PathPolicyStrict))
+	Expect(router.Register("/app/{path:.*}", NewStaticSpec(staticFS))).To(BeNil())
+	Expect(serveRequest(router, "GET", "/app/../../index.html", nil).Body.String()).To(Equal("<html>home</html>"))
+	Expect(serveRequest(router, "GET", "/app/../../etc/passwd", nil).Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *StaticSuite) TestSPAFallback(t sweet.T) {
+	router := newStaticRouter(WithSPAFallback())
+
+	recorder := serveRequest(router, "GET", "/app/users/42/settings", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(Equal("<html>home</html>"))
+
+	recorder = serveRequest(router, "GET", "/app/app.js", nil)
+	Expect(recorder.Body.String()).To(Equal("console.log('app');"))
+
+	// Missing assets are not answered with the index file
+	Expect(serveRequest(router, "GET", "/app/missing.js", nil).Code).To(Equal(http.StatusNotFound))
+
+	recorder = serveRequest(router, "GET", "/app/users/john.doe", map[string]string{"Accept": "text/html,*/*;q=0.8"})
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(Equal("<html>home</html>"))
+}
+
+func (s *StaticSuite) TestConditionalRequests(t sweet.T) {
+	router := newStaticRouter()
+	etag := serveRequest(router, "GET", "/app/app.js", nil).HeaderMap.Get("ETag")
+
+	recorder := serveRequest(router, "GET", "/app/app.js", map[string]string{"If-None-Match": etag})
+	Expect(recorder.Code).To(Equal(http.StatusNotModified))
+	Expect(recorder.Body.String()).To(BeEmpty())
+
+	recorder = serveRequest(router, "GET", "/app/app.js", map[string]string{"If-Modified-Since": "Sat, 01 Jun 2019 12:00:00 GMT"})
+	Expect(recorder.Code).To(Equal(http.StatusNotModified))
+
+	recorder = serveRequest(router, "GET", "/app/app.js", map[string]string{"If-None-Match": `"other"`})
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+}
+
+func (s *StaticSuite) TestByteRanges(t sweet.T) {
+	router := newStaticRouter()
+
+	recorder := serveRequest(router, "GET", "/app/assets/logo.txt", map[string]string{"Range": "bytes=2-5"})
+	Expect(recorder.Code).To(Equal(http.StatusPartialContent))
+	Expect(recorder.Body.String()).To(Equal("2345"))
+	Expect(recorder.HeaderMap.Get("Content-Range")).To(Equal("bytes 2-5/10"))
+
+	recorder = serveRequest(router, "GET", "/app/assets/logo.txt", map[string]string{"Range": "bytes=20-30"})
+	Expect(recorder.Code).To(Equal(http.StatusRequestedRangeNotSatisfiable))
+}
+
+func (s *StaticSuite) TestETagCache(t sweet.T) {
+	fsys := fstest.MapFS{"app.js": {Data: []byte("console.log('app');"), ModTime: staticModTime}}
+	for i := 0; i < maxCachedETags+10; i++ {
+		fsys[fmt.Sprintf("file%d.txt", i)] = &fstest.MapFile{Data: []byte(strconv.Itoa(i))}
+	}
+
+	var (
+		spec   = NewStaticSpec(fsys).(*StaticSpec)
+		router = NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	)
+
+	Expect(router.Register("/app/{path:.*}", spec)).To(BeNil())
+	etag := serveRequest(router, "GET", "/app/app.js", nil).HeaderMap.Get("ETag")
+
+	for i := 0; i < maxCachedETags+10; i++ {
+		Expect(serveRequest(router, "GET", fmt.Sprintf("/app/file%d.txt", i), nil).Code).To(Equal(http.StatusOK))
+	}
+
+	Expect(spec.etags).To(HaveLen(maxCachedETags))
+
+	// Modified files are tagged anew
+	fsys["app.js"] = &fstest.MapFile{Data: []byte("console.log('new');"), ModTime: staticModTime.Add(time.Hour)}
+	Expect(serveRequest(router, "GET", "/app/app.js", nil).HeaderMap.Get("ETag")).NotTo(Equal(etag))
+}
+
+func (s *StaticSuite) TestUnseekableFile(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(router.Register("/app/{path:.*}", NewStaticSpec(unseekableFS{staticFS}))).To(BeNil())
+
+	recorder := serveRequest(router, "GET", "/app/assets/logo.txt", map[string]string{"Range": "bytes=2-5"})
+	Expect(recorder.Code).To(Equal(http.StatusPartialContent))
+	Expect(recorder.Body.String()).To(Equal("2345"))
+}
+
+func (s *StaticSuite) TestCacheControl(t sweet.T) {
+	router := newStaticRouter(
+		WithCacheControl(".js", "public, max-age=31536000, immutable"),
+		WithCacheControl(".html", "no-cache"),
+	)
+
+	Expect(serveRequest(router, "GET", "/app/app.js", nil).HeaderMap.Get("Cache-Control")).To(Equal("public, max-age=31536000, immutable"))
+	Expect(serveRequest(router, "GET", "/app/", nil).HeaderMap.Get("Cache-Control")).To(Equal("no-cache"))
+	Expect(serveRequest(router, "GET", "/app/assets/logo.txt", nil).HeaderMap.Get("Cache-Control")).To(BeEmpty())
+}
+
+func (s *StaticSuite) TestPrecompressed(t sweet.T) {
+	router := newStaticRouter(WithPrecompressed())
+
+	recorder := serveRequest(router, "GET", "/app/app.js", map[string]string{"Accept-Encoding": "br, gzip"})
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(Equal("gzipped app"))
+	Expect(recorder.HeaderMap.Get("Content-Encoding")).To(Equal("gzip"))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal("text/javascript; charset=utf-8"))
+	Expect(recorder.HeaderMap.Get("Vary")).To(Equal("Accept-Encoding"))
+
+	recorder = serveRequest(router, "GET", "/app/app.js", map[string]string{"Accept-Encoding": "gzip;q=0"})
+	Expect(recorder.Body.String()).To(Equal("console.log('app');"))
+	Expect(recorder.HeaderMap.Get("Content-Encoding")).To(BeEmpty())
+
+	recorder = serveRequest(router, "GET", "/app/assets/logo.txt", map[string]string{"Accept-Encoding": "gzip"})
+	Expect(recorder.Body.String()).To(Equal("0123456789"))
+	Expect(recorder.HeaderMap.Get("Content-Encoding")).To(BeEmpty())
+}
+
+func (s *StaticSuite) TestDirectoryListing(t sweet.T) {
+	Expect(serveRequest(newStaticRouter(), "GET", "/app/assets/", nil).Code).To(Equal(http.StatusNotFound))
+
+	recorder := serveRequest(newStaticRouter(WithDirectoryListing()), "GET", "/app/assets/", nil)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.HeaderMap.Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+	Expect(recorder.Body.String()).To(ContainSubstring(`<a href="/app/assets/img/">img/</a>`))
+	Expect(recorder.Body.String()).To(ContainSubstring(`<a href="/app/assets/logo.txt">logo.txt</a>`))
+}
+
+func (s *StaticSuite) TestHead(t sweet.T) {
+	router := newStaticRouter()
+
+	req, _ := http.NewRequest("HEAD", "/app/app.js", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	Expect(recorder.Code).To(Equal(http.StatusOK))
+	Expect(recorder.Body.String()).To(BeEmpty())
+	Expect(recorder.HeaderMap.Get("Content-Length")).To(Equal("19"))
+}
+
+//
+//
+
+func newStaticRouter(configs ...StaticConfigFunc) Router {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(router.Register("/app/{path:.*}", NewStaticSpec(staticFS, configs...))).To(BeNil())
+	return router
+}
+
+type unseekableFS struct {
+	fsys fs.FS
+}
+
+type unseekableFile struct {
+	fs.File
+}
+
+func (f unseekableFS) Open(name string) (fs.File, error) {
+	file, err := f.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return unseekableFile{file}, nil
+}
@@ -0,0 +1,29 @@
+package chevron
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type tokenRouter string
+
+// TokenRouter is the unique token to which the router is written to the
+// request context.
+var TokenRouter = tokenRouter("chevron.router")
+
+// GetURL builds a URL for the route with the given name using the router
+// registered with the given context. Parameters are supplied as alternating
+// variable names and values. If no router is registered with this context,
+// an error is returned.
+func GetURL(ctx context.Context, name string, params ...string) (*url.URL, error) {
+	if val, ok := ctx.Value(TokenRouter).(Router); ok {
+		return val.URL(name, params...)
+	}
+
+	return nil, fmt.Errorf("no router registered to context")
+}
+
+func setRouter(ctx context.Context, router Router) context.Context {
+	return context.WithValue(ctx, TokenRouter, router)
+}
@@ -0,0 +1,233 @@
+package chevron
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+)
+
+type (
+	// VersionConfigFunc is a function used to configure API versioning.
+	VersionConfigFunc func(*versionOptions)
+
+	versionOptions struct {
+		pathPrefix     bool
+		header         string
+		vendor         string
+		defaultVersion string
+	}
+
+	tokenAPIVersion string
+)
+
+// TokenAPIVersion is the unique token to which the API version of the
+// resource serving the current request is written to the request context.
+var TokenAPIVersion = tokenAPIVersion("chevron.api_version")
+
+// GetAPIVersion retrieves the API version of the resource serving the
+// current request. If the resource was not registered via RegisterVersions,
+// the empty string is returned.
+func GetAPIVersion(ctx context.Context) string {
+	if val, ok := ctx.Value(TokenAPIVersion).(string); ok {
+		return val
+	}
+
+	return ""
+}
+
+// WithVersionFromPath registers each version of a resource under a path
+// prefix equal to the version (e.g. `/v2/users`).
+func WithVersionFromPath() VersionConfigFunc {
+	return func(o *versionOptions) { o.pathPrefix = true }
+}
+
+// WithVersionFromHeader selects the version of a resource from the value of
+// the given request header (e.g. `X-API-Version: v2`).
+func WithVersionFromHeader(name string) VersionConfigFunc {
+	return func(o *versionOptions) { o.header = name }
+}
+
+// WithVersionFromMediaType selects the version of a resource from a vendor
+// media type with the given vendor name in the Accept header (e.g. for the
+// vendor `acme`, `application/vnd.acme.v2+json`).
+func WithVersionFromMediaType(vendor string) VersionConfigFunc {
+	return func(o *versionOptions) { o.vendor = vendor }
+}
+
+// WithDefaultVersion sets the version of a resource that serves requests
+// which do not specify a version. When versions are selected by path prefix,
+// the default version is also registered without a prefix.
+func WithDefaultVersion(version string) VersionConfigFunc {
+	return func(o *versionOptions) { o.defaultVersion = version }
+}
+
+// RegisterVersions creates a resource from each of the given resource specs,
+// keyed by API version, and registers them to the given URL pattern. The
+// version serving a request is selected by a path prefix, a request header,
+// or a vendor media type in the Accept header, according to the options
+// supplied to WithVersioning (in that order of precedence), and falls back
+// to the default version. A request for an unregistered version is handled
+// by the router's not found handler (or receives a 406-level response if the
+// version was requested by media type). If any version cannot be registered,
+// none are.
+// The selected version is written to the request context and added to the
+// logger's fields. If a route name is supplied along with path prefixes, each
+// prefixed route is named with the version as a suffix (e.g. `users.v2`).
+func (r *router) RegisterVersions(url string, specs map[string]ResourceSpec, configs ...RouteConfig) error {
+	versioning := r.root.versionOptions
+	if versioning == nil {
+		return fmt.Errorf("api versioning is not enabled (use WithVersioning)")
+	}
+
+	if len(specs) == 0 {
+		return fmt.Errorf("no resource specs supplied for url pattern `%s`", r.prefix+url)
+	}
+
+	var (
+		options = getRouteOptions(configs)
+		routes  = []*pendingRoute{}
+	)
+
+	// All routes are validated before any are attached to the router
+	if versioning.pathPrefix {
+		for _, version := range sortedVersions(specs) {
+			versionOptions := *options
+			if options.name != "" {
+				versionOptions.name = options.name + "." + version
+			}
+
+			route, err := r.prepare("/"+version+url, map[string]ResourceSpec{version: specs[version]}, false, &versionOptions)
+			if err != nil {
+				return err
+			}
+
+			routes = append(routes, route)
+		}
+	}
+
+	if !versioning.pathPrefix || versioning.header != "" || versioning.vendor != "" || versioning.defaultVersion != "" {
+		route, err := r.prepare(url, specs, true, options)
+		if err != nil {
+			return err
+		}
+
+		routes = append(routes, route)
+	}
+
+	for _, route := range routes {
+		if err := r.attach(route); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dispatch creates a handler that invokes the handler of the version selected
+// by the request. Requests for an unregistered version are passed to the given
+// not found handler unless the version was requested by media type.
+func (o *versionOptions) dispatch(handlers map[string]Handler, notFound Handler) Handler {
+	return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		version, byMediaType := o.resolve(req)
+
+		var resp response.Response
+		if handler, ok := handlers[version]; ok {
+			resp = handler(ctx, req, logger)
+		} else if byMediaType {
+			resp = DefaultResponse(ctx, req, http.StatusNotAcceptable)
+		} else {
+			resp = notFound(ctx, req, logger)
+		}
+
+		if o.header != "" {
+			resp.AddHeader("Vary", o.header)
+		}
+
+		if o.vendor != "" {
+			resp.AddHeader("Vary", "Accept")
+		}
+
+		return resp
+	}
+}
+
+// resolve returns the version requested by the given request and whether or
+// not the version was requested by media type.
+func (o *versionOptions) resolve(req *http.Request) (string, bool) {
+	if o.header != "" {
+		if version := req.Header.Get(o.header); version != "" {
+			return version, false
+		}
+	}
+
+	if o.vendor != "" {
+		if version := o.mediaTypeVersion(req.Header.Get("Accept")); version != "" {
+			return version, true
+		}
+	}
+
+	return o.defaultVersion, false
+}
+
+// mediaTypeVersion returns the version of the most preferred vendor media
+// type in the given Accept header.
+func (o *versionOptions) mediaTypeVersion(accept string) string {
+	var (
+		prefix      = "application/vnd." + strings.ToLower(o.vendor) + "."
+		best        = ""
+		bestQuality = 0.0
+	)
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || !strings.HasPrefix(mediaType, prefix) {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		if quality > bestQuality {
+			best = strings.SplitN(strings.TrimPrefix(mediaType, prefix), "+", 2)[0]
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
+
+// withAPIVersion decorates the given handler so that the given version is
+// written to the request context and the logger's fields. The handler is
+// returned unchanged if the version is empty.
+func withAPIVersion(version string, handler Handler) Handler {
+	if version == "" {
+		return handler
+	}
+
+	return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		ctx = context.WithValue(ctx, TokenAPIVersion, version)
+		logger = logger.WithFields(nacelle.LogFields{"api_version": version})
+		return handler(ctx, req, logger)
+	}
+}
+
+func sortedVersions(specs map[string]ResourceSpec) []string {
+	versions := make([]string, 0, len(specs))
+	for version := range specs {
+		versions = append(versions, version)
+	}
+
+	sort.Strings(versions)
+	return versions
+}
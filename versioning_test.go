@@ -0,0 +1,179 @@
+package chevron
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aphistic/sweet"
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+	. "github.com/onsi/gomega"
+)
+
+type VersioningSuite struct{}
+
+func (s *VersioningSuite) TestNotEnabled(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger())
+	Expect(router.RegisterVersions("/users", versionedSpecs())).To(MatchError("api versioning is not enabled (use WithVersioning)"))
+}
+
+func (s *VersioningSuite) TestPathPrefix(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithVersioning(WithVersionFromPath()))
+	Expect(router.RegisterVersions("/users", versionedSpecs(), WithName("users"))).To(BeNil())
+
+	Expect(serveRequest(router, "GET", "/v1/users", nil).Body.String()).To(MatchJSON(`"v1"`))
+	Expect(serveRequest(router, "GET", "/v2/users", nil).Body.String()).To(MatchJSON(`"v2"`))
+	Expect(serveRequest(router, "GET", "/v3/users", nil).Code).To(Equal(http.StatusNotFound))
+	Expect(serveRequest(router, "GET", "/users", nil).Code).To(Equal(http.StatusNotFound))
+
+	url, err := router.URL("users.v2")
+	Expect(err).To(BeNil())
+	Expect(url.String()).To(Equal("/v2/users"))
+}
+
+func (s *VersioningSuite) TestPathPrefixDefault(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithVersioning(
+		WithVersionFromPath(),
+		WithDefaultVersion("v1"),
+	))
+
+	Expect(router.RegisterVersions("/users", versionedSpecs())).To(BeNil())
+	Expect(serveRequest(router, "GET", "/users", nil).Body.String()).To(MatchJSON(`"v1"`))
+	Expect(serveRequest(router, "GET", "/v2/users", nil).Body.String()).To(MatchJSON(`"v2"`))
+}
+
+func (s *VersioningSuite) TestHeader(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithVersioning(
+		WithVersionFromHeader("X-API-Version"),
+		WithDefaultVersion("v1"),
+	))
+
+	Expect(router.RegisterVersions("/users", versionedSpecs())).To(BeNil())
+
+	recorder := serveRequest(router, "GET", "/users", map[string]string{"X-API-Version": "v2"})
+	Expect(recorder.Body.String()).To(MatchJSON(`"v2"`))
+	Expect(recorder.HeaderMap.Get("Vary")).To(Equal("X-API-Version"))
+
+	Expect(serveRequest(router, "GET", "/users", nil).Body.String()).To(MatchJSON(`"v1"`))
+	Expect(serveRequest(router, "GET", "/users", map[string]string{"X-API-Version": "v9"}).Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *VersioningSuite) TestUnknownVersionNotFoundHandler(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(),
+		WithNotFoundHandler(func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+			return response.JSON("custom").SetStatusCode(http.StatusNotFound)
+		}),
+		WithVersioning(WithVersionFromHeader("X-API-Version")),
+	)
+
+	Expect(router.RegisterVersions("/users", versionedSpecs())).To(BeNil())
+
+	recorder := serveRequest(router, "GET", "/users", map[string]string{"X-API-Version": "v9"})
+	Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	Expect(recorder.Body.String()).To(MatchJSON(`"custom"`))
+	Expect(recorder.HeaderMap.Get("Vary")).To(Equal("X-API-Version"))
+}
+
+func (s *VersioningSuite) TestPathPrefixAtomic(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithVersioning(
+		WithVersionFromPath(),
+		WithDefaultVersion("v1"),
+	))
+
+	Expect(router.Register("/users", &EmptySpec{})).To(BeNil())
+	Expect(router.RegisterVersions("/users", versionedSpecs(), WithName("users"))).NotTo(BeNil())
+
+	// No version is registered if any version fails
+	Expect(router.Routes()).To(HaveLen(1))
+	Expect(serveRequest(router, "GET", "/v1/users", nil).Code).To(Equal(http.StatusNotFound))
+
+	_, err := router.URL("users.v1")
+	Expect(err).NotTo(BeNil())
+}
+
+func (s *VersioningSuite) TestMediaType(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithVersioning(
+		WithVersionFromMediaType("acme"),
+		WithDefaultVersion("v2"),
+	))
+
+	Expect(router.RegisterVersions("/users", versionedSpecs())).To(BeNil())
+
+	recorder := serveRequest(router, "GET", "/users", map[string]string{"Accept": "application/vnd.acme.v1+json"})
+	Expect(recorder.Body.String()).To(MatchJSON(`"v1"`))
+	Expect(recorder.HeaderMap.Get("Vary")).To(Equal("Accept"))
+
+	recorder = serveRequest(router, "GET", "/users", map[string]string{"Accept": "application/vnd.acme.v1+json;q=0.5, application/vnd.acme.v2+json"})
+	Expect(recorder.Body.String()).To(MatchJSON(`"v2"`))
+
+	recorder = serveRequest(router, "GET", "/users", map[string]string{"Accept": "application/json"})
+	Expect(recorder.Body.String()).To(MatchJSON(`"v2"`))
+
+	recorder = serveRequest(router, "GET", "/users", map[string]string{"Accept": "application/vnd.acme.v7+json"})
+	Expect(recorder.Code).To(Equal(http.StatusNotAcceptable))
+}
+
+func (s *VersioningSuite) TestHeaderPrecedence(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithVersioning(
+		WithVersionFromHeader("X-API-Version"),
+		WithVersionFromMediaType("acme"),
+	))
+
+	Expect(router.RegisterVersions("/users", versionedSpecs())).To(BeNil())
+
+	recorder := serveRequest(router, "GET", "/users", map[string]string{
+		"X-API-Version": "v1",
+		"Accept":        "application/vnd.acme.v2+json",
+	})
+
+	Expect(recorder.Body.String()).To(MatchJSON(`"v1"`))
+	Expect(recorder.HeaderMap["Vary"]).To(Equal([]string{"X-API-Version", "Accept"}))
+	Expect(serveRequest(router, "GET", "/users", nil).Code).To(Equal(http.StatusNotFound))
+}
+
+func (s *VersioningSuite) TestLoggerFields(t sweet.T) {
+	logger := &fieldLogger{Logger: nacelle.NewNilLogger()}
+	router := NewRouter(nacelle.NewServiceContainer(), logger, WithVersioning(WithVersionFromPath()))
+
+	Expect(router.RegisterVersions("/users", map[string]ResourceSpec{"v3": &HandlerSpec{handler: okHandler}})).To(BeNil())
+	Expect(serveRequest(router, "GET", "/v3/users", nil).Code).To(Equal(http.StatusOK))
+	Expect(logger.fields).To(Equal(nacelle.LogFields{"api_version": "v3"}))
+}
+
+func (s *VersioningSuite) TestRoutes(t sweet.T) {
+	router := NewRouter(nacelle.NewServiceContainer(), nacelle.NewNilLogger(), WithVersioning(WithVersionFromHeader("X-API-Version")))
+	Expect(router.RegisterVersions("/users", versionedSpecs())).To(BeNil())
+	Expect(router.Register("/teams", &HandlerSpec{handler: okHandler})).To(BeNil())
+
+	routes := router.Routes()
+	Expect(routes).To(HaveLen(3))
+	Expect(routes[0].Pattern).To(Equal("/users"))
+	Expect(routes[0].Version).To(Equal("v1"))
+	Expect(routes[1].Pattern).To(Equal("/users"))
+	Expect(routes[1].Version).To(Equal("v2"))
+	Expect(routes[2].Version).To(BeEmpty())
+}
+
+//
+//
+
+func versionedSpecs() map[string]ResourceSpec {
+	handler := func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return response.JSON(GetAPIVersion(ctx))
+	}
+
+	return map[string]ResourceSpec{
+		"v1": &HandlerSpec{handler: handler},
+		"v2": &HandlerSpec{handler: handler},
+	}
+}
+
+type fieldLogger struct {
+	nacelle.Logger
+	fields nacelle.LogFields
+}
+
+func (l *fieldLogger) WithFields(fields nacelle.LogFields) nacelle.Logger {
+	l.fields = fields
+	return l
+}
@@ -0,0 +1,95 @@
+package chevron
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/efritz/response"
+	"github.com/go-nacelle/nacelle"
+)
+
+// responseBuffer is an http.ResponseWriter which buffers the status code,
+// headers, and body written by a handler. Headers modified after the status
+// code is written are ignored, as they would be by a real ResponseWriter.
+type responseBuffer struct {
+	header      http.Header
+	snapshot    http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// WrapHandler converts an http.Handler into a Handler. The status code,
+// headers, and body written by the wrapped handler are buffered and
+// returned as a response object, which allows chevron middleware to
+// decorate handlers that are not aware of chevron. The wrapped handler
+// is invoked with a request carrying the handler's context.
+//
+// As the output of the handler is buffered, the ResponseWriter supplied
+// to it does not implement http.Flusher or http.Hijacker. Handlers which
+// stream their response or take over the connection should instead be
+// registered via RegisterHandler without middleware configs and must not
+// be placed behind a pipeline middleware, which buffers all responses.
+func WrapHandler(handler http.Handler) Handler {
+	return func(ctx context.Context, req *http.Request, logger nacelle.Logger) response.Response {
+		return captureResponse(handler, req.WithContext(ctx))
+	}
+}
+
+// captureResponse invokes the given handler and converts its output into
+// a response object.
+func captureResponse(handler http.Handler, req *http.Request) response.Response {
+	buffer := newResponseBuffer()
+	handler.ServeHTTP(buffer, req)
+	return buffer.response()
+}
+
+func newResponseBuffer() *responseBuffer {
+	return &responseBuffer{
+		header: http.Header{},
+		status: http.StatusOK,
+	}
+}
+
+// Header returns the header map which will be sent by WriteHeader.
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+// WriteHeader records the given status code and the current headers. Only
+// the first call has an effect.
+func (b *responseBuffer) WriteHeader(status int) {
+	if b.wroteHeader {
+		return
+	}
+
+	b.status = status
+	b.snapshot = b.header.Clone()
+	b.wroteHeader = true
+}
+
+// Write buffers the given data as part of the response body. If no status
+// code has been written, a 200 status is written first and the content type
+// is detected from the data if the handler did not set one.
+func (b *responseBuffer) Write(data []byte) (int, error) {
+	if !b.wroteHeader {
+		if _, ok := b.header["Content-Type"]; !ok && len(data) > 0 {
+			b.header.Set("Content-Type", http.DetectContentType(data))
+		}
+
+		b.WriteHeader(http.StatusOK)
+	}
+
+	return b.body.Write(data)
+}
+
+// response converts the buffered output into a response object.
+func (b *responseBuffer) response() response.Response {
+	header := b.snapshot
+	if !b.wroteHeader {
+		header = b.header
+	}
+
+	return response.Reconstruct(b.status, header, b.body.Bytes())
+}